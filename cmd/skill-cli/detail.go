@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/smallnest/goskills"
@@ -10,11 +11,14 @@ import (
 )
 
 var detailCmd = &cobra.Command{
-	Use:   "detail <skill_directory>",
+	Use:   "detail <skill_directory|name>",
 	Short: "Displays detailed information about a skill package.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		skillDir := args[0]
+		skillDir, err := resolveSkillDir(args[0])
+		if err != nil {
+			return err
+		}
 		absSkillDir, err := filepath.Abs(skillDir)
 		if err != nil {
 			return fmt.Errorf("failed to get absolute path for %s: %w", skillDir, err)
@@ -29,6 +33,9 @@ var detailCmd = &cobra.Command{
 		fmt.Printf("Path: %s\n", skillPackage.Path)
 		fmt.Printf("Description: %s\n", skillPackage.Meta.Description)
 		fmt.Printf("Allowed Tools: %s\n", strings.Join(skillPackage.Meta.AllowedTools, ", "))
+		if len(skillPackage.Meta.DisallowedTools) > 0 {
+			fmt.Printf("Disallowed Tools: %s\n", strings.Join(skillPackage.Meta.DisallowedTools, ", "))
+		}
 		if skillPackage.Meta.Model != "" {
 			fmt.Printf("Model: %s\n", skillPackage.Meta.Model)
 		}
@@ -41,6 +48,50 @@ var detailCmd = &cobra.Command{
 		if skillPackage.Meta.License != "" {
 			fmt.Printf("License: %s\n", skillPackage.Meta.License)
 		}
+		if len(skillPackage.Meta.Requires) > 0 {
+			fmt.Println("Requires:")
+			for _, dep := range skillPackage.Meta.Requires {
+				fmt.Printf("  - %s\n", dep)
+			}
+		}
+		if len(skillPackage.Meta.Metadata) > 0 {
+			fmt.Println("Metadata:")
+			keys := make([]string, 0, len(skillPackage.Meta.Metadata))
+			for k := range skillPackage.Meta.Metadata {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Printf("  %s: %s\n", k, skillPackage.Meta.Metadata[k])
+			}
+		}
+
+		if headings := skillPackage.Document.Headings(); len(headings) > 0 {
+			fmt.Println("\n--- Outline ---")
+			for _, h := range headings {
+				fmt.Printf("%s- %s (#%s, line %d)\n", strings.Repeat("  ", h.Level-1), h.Title, h.Anchor, h.Line)
+			}
+		}
+		if len(skillPackage.Document.CodeBlocks) > 0 {
+			counts := make(map[string]int)
+			for _, b := range skillPackage.Document.CodeBlocks {
+				lang := b.Language
+				if lang == "" {
+					lang = "plain"
+				}
+				counts[lang]++
+			}
+			langs := make([]string, 0, len(counts))
+			for lang := range counts {
+				langs = append(langs, lang)
+			}
+			sort.Strings(langs)
+			parts := make([]string, 0, len(langs))
+			for _, lang := range langs {
+				parts = append(parts, fmt.Sprintf("%s: %d", lang, counts[lang]))
+			}
+			fmt.Printf("Code Blocks: %s\n", strings.Join(parts, ", "))
+		}
 
 		fmt.Println("\n--- SKILL.md Body ---")
 		fmt.Println(skillPackage.Body) // Directly print the raw markdown body
@@ -64,7 +115,13 @@ var detailCmd = &cobra.Command{
 				fmt.Printf("  - %s\n", a)
 			}
 		}
-		if len(skillPackage.Resources.Scripts) == 0 && len(skillPackage.Resources.References) == 0 && len(skillPackage.Resources.Assets) == 0 {
+		if len(skillPackage.Resources.Templates) > 0 {
+			fmt.Println("Templates:")
+			for _, t := range skillPackage.Resources.Templates {
+				fmt.Printf("  - %s\n", t)
+			}
+		}
+		if len(skillPackage.Resources.Files) == 0 {
 			fmt.Println("No resources found.")
 		}
 
@@ -3,19 +3,25 @@ package main
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/smallnest/goskills"
 	"github.com/spf13/cobra"
 )
 
 var filesCmd = &cobra.Command{
-	Use:   "files [path]",
+	Use:   "files <path|name>",
 	Short: "Lists all files comprising a skill package.",
 	Long: `The files command parses a skill package and lists all the files that make it up,
-including the SKILL.md file and all discovered resource files.`,
+including the SKILL.md file and every other file in the skill directory, each
+annotated with its classified kind (script, reference, asset, template, ...),
+its size and media type. Executable files and symbolic links are marked.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		skillPath := args[0]
+		skillPath, err := resolveSkillDir(args[0])
+		if err != nil {
+			return err
+		}
 		skillPackage, err := goskills.ParseSkillPackage(skillPath)
 		if err != nil {
 			return fmt.Errorf("failed to parse skill: %w", err)
@@ -26,16 +32,22 @@ including the SKILL.md file and all discovered resource files.`,
 		// Add the SKILL.md file itself
 		fmt.Printf("- %s\n", filepath.Join(skillPackage.Path, "SKILL.md"))
 
-		// Add all resource files
-		resources := skillPackage.Resources
-		for _, file := range resources.Scripts {
-			fmt.Printf("- %s\n", filepath.Join(skillPackage.Path, file))
-		}
-		for _, file := range resources.References {
-			fmt.Printf("- %s\n", filepath.Join(skillPackage.Path, file))
-		}
-		for _, file := range resources.Assets {
-			fmt.Printf("- %s\n", filepath.Join(skillPackage.Path, file))
+		// Add all resource files from the package inventory
+		for _, file := range skillPackage.Resources.Files {
+			details := []string{string(file.Kind), fmt.Sprintf("%d bytes", file.Size)}
+			if file.MIME != "" {
+				details = append(details, file.MIME)
+			}
+			if file.Executable {
+				details = append(details, "executable")
+			}
+			switch {
+			case file.EscapesRoot:
+				details = append(details, "symlink outside the skill")
+			case file.Symlink:
+				details = append(details, "symlink")
+			}
+			fmt.Printf("- %s (%s)\n", filepath.Join(skillPackage.Path, file.Path), strings.Join(details, ", "))
 		}
 
 		return nil
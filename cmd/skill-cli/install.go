@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install <archive>",
+	Short: "Installs a packed skill into a skills directory.",
+	Long: `The install command verifies a skill archive made by pack against its
+manifest and installs it into a skills directory. Archives with entries outside
+the skill directory, links, or files that do not match the manifest are refused.
+
+An installed copy of the skill is only replaced by a newer version; use --force
+to reinstall the same version or downgrade. Without --to, the skill is installed
+into the user skills root (~/.config/goskills/skills).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		if to == "" {
+			for _, root := range goskills.DefaultRoots(".") {
+				if root.Scope == goskills.ScopeUser {
+					to = root.Path
+				}
+			}
+			if to == "" {
+				return errors.New("no user skills directory; pass --to")
+			}
+		}
+		var opts []goskills.InstallOption
+		if force {
+			opts = append(opts, goskills.WithForce())
+		}
+
+		result, err := goskills.InstallSkill(args[0], to, opts...)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to install skill: %w", err)
+		}
+
+		version := result.Version
+		if version == "" {
+			version = "(unversioned)"
+		}
+		if result.Replaced {
+			previous := result.Previous
+			if previous == "" {
+				previous = "(unversioned)"
+			}
+			switch result.VersionChange() {
+			case 1:
+				fmt.Printf("Upgraded %s from %s to %s in %s\n", result.Name, previous, version, result.Path)
+			case -1:
+				fmt.Printf("Downgraded %s from %s to %s in %s\n", result.Name, previous, version, result.Path)
+			default:
+				fmt.Printf("Reinstalled %s %s in %s\n", result.Name, version, result.Path)
+			}
+		} else {
+			fmt.Printf("Installed %s %s into %s\n", result.Name, version, result.Path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	installCmd.Flags().String("to", "", "Skills directory to install into")
+	installCmd.Flags().Bool("force", false, "Replace an installed copy even if it is the same or a newer version")
+	rootCmd.AddCommand(installCmd)
+}
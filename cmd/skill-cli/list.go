@@ -2,33 +2,96 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/smallnest/goskills"
 	"github.com/spf13/cobra"
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list [path]",
-	Short: "Lists all valid skills in a given directory.",
-	Long: `The list command scans a directory for subdirectories that are valid 
-Claude skill packages and prints a summary of each one found.`,
-	Args: cobra.ExactArgs(1),
+	Use:   "list [path]...",
+	Short: "Lists all valid skills in the given directories.",
+	Long: `The list command scans directories for subdirectories that are valid
+Claude skill packages and prints a summary of each one found.
+
+Directories are searched in the order given; when skills in several of them
+share a name, the first one wins and the others are reported as shadowed.
+Without a path, the project (.goskills/skills), user (~/.config/goskills/skills)
+and system (/etc/goskills/skills) roots are searched.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		skillsRoot := args[0]
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		sortBy, err := cmd.Flags().GetString("sort")
+		if err != nil {
+			return err
+		}
 
-		packages, err := goskills.ParseSkillPackages(skillsRoot)
+		tag, err := cmd.Flags().GetString("tag")
+		if err != nil {
+			return err
+		}
+
+		var opts []goskills.ParseOption
+		if strict {
+			opts = append(opts, goskills.WithStrict())
+		}
+		switch sortBy {
+		case "path":
+		case "name":
+			opts = append(opts, goskills.WithSortByName())
+		default:
+			return fmt.Errorf("unknown sort order '%s' (expected path or name)", sortBy)
+		}
+		roots := skillRoots(args)
+		registry, err := goskills.NewRegistry(roots, opts...)
 		if err != nil {
-			return fmt.Errorf("could not parse skills in directory '%s': %w", skillsRoot, err)
+			return fmt.Errorf("could not load skills: %w", err)
+		}
+
+		skills := registry.Skills()
+		if tag != "" {
+			skills = registry.WithTag(tag)
 		}
 
-		fmt.Printf("--- Skills found in %s ---\n", skillsRoot)
-		if len(packages) == 0 {
+		paths := make([]string, 0, len(roots))
+		for _, root := range registry.Roots() {
+			paths = append(paths, root.Path)
+		}
+		fmt.Printf("--- Skills found in %s ---\n", strings.Join(paths, ", "))
+		if len(skills) == 0 {
 			fmt.Println("No valid skills found.")
-			return nil
 		}
 
-		for _, skillPackage := range packages {
-			fmt.Printf("- %-20s: %s\n", skillPackage.Meta.Name, skillPackage.Meta.Description)
+		for _, skill := range skills {
+			fmt.Printf("- %-20s: %s\n", skill.Meta.Name, skill.Meta.Description)
+		}
+
+		if collisions := registry.Collisions(); len(collisions) > 0 {
+			fmt.Printf("\n--- Name collisions: %d ---\n", len(collisions))
+			for _, c := range collisions {
+				fmt.Printf("- %s (%s): using %s\n", c.Name, c.Kind, c.Active.Path)
+				for _, hidden := range c.Hidden {
+					fmt.Printf("    hides %s\n", hidden.Path)
+				}
+			}
+		}
+
+		if problems := registry.DependencyProblems(); len(problems) > 0 {
+			fmt.Printf("\n--- Unresolved dependencies: %d ---\n", len(problems))
+			for _, p := range problems {
+				fmt.Printf("- %s\n", p)
+			}
+		}
+
+		if failures := registry.Failures(); len(failures) > 0 {
+			fmt.Printf("\n--- Warnings: %d skill(s) failed to parse ---\n", len(failures))
+			for _, failure := range failures {
+				fmt.Printf("- %s\n    %v\n", failure.Dir, failure.Err)
+			}
 		}
 
 		return nil
@@ -36,5 +99,8 @@ Claude skill packages and prints a summary of each one found.`,
 }
 
 func init() {
+	listCmd.Flags().Bool("strict", false, "Fail on the first skill that cannot be parsed")
+	listCmd.Flags().String("sort", "path", "Order skills by 'path' or 'name'")
+	listCmd.Flags().String("tag", "", "Only list skills with this tag")
 	rootCmd.AddCommand(listCmd)
 }
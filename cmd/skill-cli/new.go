@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Creates a new skill from a template.",
+	Long: `The new command creates a skill directory named <name> containing a SKILL.md with
+spec-compliant frontmatter and placeholder instructions. The name must be
+hyphen-case: lowercase letters and digits separated by single hyphens.
+
+Use --resources to add scripts/, references/, assets/ or templates/ directories
+with an example file each, --license to add a LICENSE.txt, and --template to
+start from the files and instructions of an existing skill.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		description, err := cmd.Flags().GetString("description")
+		if err != nil {
+			return err
+		}
+		allowedTools, err := cmd.Flags().GetStringSlice("allowed-tools")
+		if err != nil {
+			return err
+		}
+		resources, err := cmd.Flags().GetStringSlice("resources")
+		if err != nil {
+			return err
+		}
+		license, err := cmd.Flags().GetString("license")
+		if err != nil {
+			return err
+		}
+		template, err := cmd.Flags().GetString("template")
+		if err != nil {
+			return err
+		}
+
+		opts := []goskills.ScaffoldOption{
+			goskills.WithDescription(description),
+			goskills.WithAllowedTools(allowedTools...),
+			goskills.WithResourceDirs(resources...),
+			goskills.WithLicense(license),
+		}
+		if template != "" {
+			templatePath, err := resolveSkillDir(template)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, goskills.WithTemplate(templatePath))
+		}
+
+		skillPackage, err := goskills.CreateSkill(dir, args[0], opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create skill: %w", err)
+		}
+
+		fmt.Printf("Created skill %s in %s\n", skillPackage.Meta.Name, skillPackage.Path)
+		fmt.Println("- SKILL.md")
+		for _, file := range skillPackage.Resources.Files {
+			fmt.Printf("- %s\n", file.Path)
+		}
+		fmt.Println("\nNext, complete the TODO items in SKILL.md and run 'goskills-cli validate' on the skill.")
+		return nil
+	},
+}
+
+func init() {
+	newCmd.Flags().String("dir", ".", "Directory to create the skill in")
+	newCmd.Flags().String("description", "", "What the skill does and when to use it")
+	newCmd.Flags().StringSlice("allowed-tools", nil, "Comma-separated list of tools the skill may use")
+	newCmd.Flags().StringSlice("resources", nil, "Comma-separated resource directories to create: scripts, references, assets, templates")
+	newCmd.Flags().String("license", "", "License of the skill, such as an SPDX identifier; adds a LICENSE.txt")
+	newCmd.Flags().String("template", "", "Skill directory or name to copy files and instructions from")
+	rootCmd.AddCommand(newCmd)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var packCmd = &cobra.Command{
+	Use:   "pack <path|name>",
+	Short: "Packs a skill into a distributable archive.",
+	Long: `The pack command writes a skill to a zip (.skill) or tar.gz archive together
+with a manifest listing the size and SHA-256 of every file and the skill's
+metadata. Packing the same files always produces the same archive.
+
+By default the archive is written to <name>-<version>.skill in the current
+directory, or <name>-<version>.tar.gz with --format tar.gz.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		skillPath, err := resolveSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+		skillPackage, err := goskills.ParseSkillPackage(skillPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse skill: %w", err)
+		}
+
+		if output == "" {
+			output, err = defaultArchiveName(skillPackage.Meta, goskills.ArchiveFormat(format))
+			if err != nil {
+				return err
+			}
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		manifest, err := goskills.PackSkill(skillPackage, f, goskills.ArchiveFormat(format))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(output)
+			return fmt.Errorf("failed to pack skill: %w", err)
+		}
+
+		fmt.Printf("Packed %s (%d files) into %s\n", manifest.Name, len(manifest.Files), output)
+		return nil
+	},
+}
+
+// defaultArchiveName returns <name>-<version> with the extension of format.
+// The name and version come from the skill, so they must not name a path
+// outside the current directory: the version must be a semantic version and
+// the name a single file name.
+func defaultArchiveName(meta goskills.SkillMeta, format goskills.ArchiveFormat) (string, error) {
+	name := meta.Name
+	if name == "" || name != filepath.Base(name) || name == ".." {
+		return "", fmt.Errorf("skill name '%s' cannot be used as an archive name; use --output", meta.Name)
+	}
+	if meta.Version != "" {
+		if _, err := goskills.ParseVersion(meta.Version); err != nil {
+			return "", fmt.Errorf("%w; use --output to name the archive", err)
+		}
+		name += "-" + meta.Version
+	}
+	if format == goskills.ArchiveZip {
+		return name + ".skill", nil
+	}
+	return name + "." + string(format), nil
+}
+
+func init() {
+	packCmd.Flags().StringP("output", "o", "", "Archive file to write")
+	packCmd.Flags().StringP("format", "f", string(goskills.ArchiveZip), "Archive format: zip or tar.gz")
+	rootCmd.AddCommand(packCmd)
+}
@@ -10,11 +10,14 @@ import (
 )
 
 var parseCmd = &cobra.Command{
-	Use:   "parse <skill_directory>",
+	Use:   "parse <skill_directory|name>",
 	Short: "Parses a skill directory and prints its metadata and a snippet of its body.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		skillDir := args[0]
+		skillDir, err := resolveSkillDir(args[0])
+		if err != nil {
+			return err
+		}
 		absSkillDir, err := filepath.Abs(skillDir)
 		if err != nil {
 			return fmt.Errorf("failed to get absolute path for %s: %w", skillDir, err)
@@ -28,6 +31,9 @@ var parseCmd = &cobra.Command{
 		fmt.Printf("Skill Name: %s\n", skillPackage.Meta.Name)
 		fmt.Printf("Description: %s\n", skillPackage.Meta.Description)
 		fmt.Printf("Allowed Tools: %s\n", strings.Join(skillPackage.Meta.AllowedTools, ", "))
+		if len(skillPackage.Meta.DisallowedTools) > 0 {
+			fmt.Printf("Disallowed Tools: %s\n", strings.Join(skillPackage.Meta.DisallowedTools, ", "))
+		}
 		if skillPackage.Meta.Model != "" {
 			fmt.Printf("Model: %s\n", skillPackage.Meta.Model)
 		}
@@ -67,7 +73,13 @@ var parseCmd = &cobra.Command{
 				fmt.Printf("  - %s\n", a)
 			}
 		}
-		if len(skillPackage.Resources.Scripts) == 0 && len(skillPackage.Resources.References) == 0 && len(skillPackage.Resources.Assets) == 0 {
+		if len(skillPackage.Resources.Templates) > 0 {
+			fmt.Println("Templates:")
+			for _, t := range skillPackage.Resources.Templates {
+				fmt.Printf("  - %s\n", t)
+			}
+		}
+		if len(skillPackage.Resources.Files) == 0 {
 			fmt.Println("No resources found.")
 		}
 
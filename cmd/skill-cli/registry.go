@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/smallnest/goskills"
+)
+
+// skillRoots turns the directories given on the command line into registry
+// roots, in order of precedence. Without directories, the default project,
+// user and system roots are used.
+func skillRoots(dirs []string) []goskills.Root {
+	if len(dirs) == 0 {
+		return goskills.DefaultRoots(".")
+	}
+	roots := make([]goskills.Root, 0, len(dirs))
+	for _, dir := range dirs {
+		roots = append(roots, goskills.Root{Path: dir, Scope: "arg"})
+	}
+	return roots
+}
+
+// resolveSkillDir returns arg when it is a directory. Otherwise arg is taken
+// as a skill name and looked up in the default roots.
+func resolveSkillDir(arg string) (string, error) {
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return arg, nil
+	}
+	registry, err := goskills.NewRegistry(goskills.DefaultRoots("."))
+	if err != nil {
+		return "", err
+	}
+	if skill, ok := registry.Lookup(arg); ok {
+		return skill.Path, nil
+	}
+	return "", fmt.Errorf("no skill directory or installed skill named '%s'", arg)
+}
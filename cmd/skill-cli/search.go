@@ -10,29 +10,30 @@ import (
 
 var searchCmd = &cobra.Command{
 	Use:   "search [path] [query]",
-	Short: "Searches for skills by name or description.",
+	Short: "Searches for skills by name, description or tag.",
 	Long: `The search command scans a directory for valid skill packages and returns a list
-	of skills where the name or description contains the provided query text.
-	The search is case-insensitive.`,
+	of skills where the name, description or one of the tags contains the provided
+	query text. The search is case-insensitive.`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		skillsRoot := args[0]
 		query := strings.ToLower(args[1])
 
-		packages, err := goskills.ParseSkillPackages(skillsRoot)
+		registry, err := goskills.NewRegistry(skillRoots([]string{skillsRoot}))
 		if err != nil {
 			return fmt.Errorf("could not parse skills in directory '%s': %w", skillsRoot, err)
 		}
 
 		fmt.Printf("--- Searching for '%s' in %s ---\n", query, skillsRoot)
 		foundCount := 0
-		for _, skillPackage := range packages {
-			// Case-insensitive search in name and description
-			name := strings.ToLower(skillPackage.Meta.Name)
-			description := strings.ToLower(skillPackage.Meta.Description)
-
-			if strings.Contains(name, query) || strings.Contains(description, query) {
-				fmt.Printf("- %-20s: %s\n", skillPackage.Meta.Name, skillPackage.Meta.Description)
+		for _, skill := range registry.Skills() {
+			// Case-insensitive search in name, description and tags
+			name := strings.ToLower(skill.Meta.Name)
+			description := strings.ToLower(skill.Meta.Description)
+			tags := strings.ToLower(strings.Join(skill.Meta.Tags(), " "))
+
+			if strings.Contains(name, query) || strings.Contains(description, query) || strings.Contains(tags, query) {
+				fmt.Printf("- %-20s: %s\n", skill.Meta.Name, skill.Meta.Description)
 				foundCount++
 			}
 		}
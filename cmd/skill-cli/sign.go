@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen <file>",
+	Short: "Generates a key pair for signing skills.",
+	Long: `The keygen command generates an ed25519 key pair. The private key is written to
+<file> in PEM form, readable only by its owner, and the public key to <file>.pub
+in the form used by trusted keys files, so it can be appended to one.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		comment, err := cmd.Flags().GetString("comment")
+		if err != nil {
+			return err
+		}
+
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			return err
+		}
+		pemData, err := goskills.MarshalPrivateKey(priv)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(args[0]); err == nil {
+			return fmt.Errorf("%s already exists", args[0])
+		}
+		if err := os.WriteFile(args[0], pemData, 0o600); err != nil {
+			return err
+		}
+		if err := os.WriteFile(args[0]+".pub", []byte(goskills.FormatPublicKey(pub, comment)+"\n"), 0o644); err != nil {
+			return err
+		}
+
+		fmt.Printf("Generated key %s\n", goskills.KeyID(pub))
+		fmt.Printf("Private key: %s\n", args[0])
+		fmt.Printf("Public key:  %s.pub\n", args[0])
+		return nil
+	},
+}
+
+var signCmd = &cobra.Command{
+	Use:   "sign <path|name>",
+	Short: "Signs a skill with a private key.",
+	Long: `The sign command writes a manifest of the skill's files and an ed25519 signature
+of it into the skill directory. The signature is carried by pack and install and
+is checked by verify and by goskills-runner. Sign again after changing the skill.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyFile, err := cmd.Flags().GetString("key")
+		if err != nil {
+			return err
+		}
+		if keyFile == "" {
+			return errors.New("a private key is required; pass --key")
+		}
+		pemData, err := os.ReadFile(keyFile)
+		if err != nil {
+			return err
+		}
+		priv, err := goskills.ParsePrivateKey(pemData)
+		if err != nil {
+			return fmt.Errorf("%s: %w", keyFile, err)
+		}
+
+		skillPath, err := resolveSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+		sig, err := goskills.SignSkill(skillPath, priv)
+		if err != nil {
+			return fmt.Errorf("failed to sign skill: %w", err)
+		}
+
+		fmt.Printf("Signed %s with key %s\n", skillPath, sig.KeyID)
+		return nil
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <path|name>...",
+	Short: "Verifies the signatures of skills.",
+	Long: `The verify command checks that each skill is signed by a trusted key and that
+its files have not changed since. Trusted keys are read from --trusted-keys,
+~/.config/goskills/trusted_keys by default. The command exits with a non-zero
+status if any skill fails verification.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keysFile, err := cmd.Flags().GetString("trusted-keys")
+		if err != nil {
+			return err
+		}
+		keys, err := goskills.ReadTrustedKeys(keysFile)
+		if err != nil {
+			return fmt.Errorf("failed to read trusted keys: %w", err)
+		}
+
+		failed := 0
+		for _, arg := range args {
+			skillPath, sig, err := verifySkillArg(arg, keys)
+			if err != nil {
+				failed++
+				fmt.Printf("✘ %s: %v\n", arg, err)
+				continue
+			}
+			fmt.Printf("✔ %s: signed by %s\n", skillPath, sig.KeyID)
+		}
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d skill(s) failed verification", failed)
+		}
+		return nil
+	},
+}
+
+// verifySkillArg resolves a skill directory or name and verifies its signature.
+func verifySkillArg(arg string, keys goskills.TrustedKeys) (string, *goskills.Signature, error) {
+	skillPath, err := resolveSkillDir(arg)
+	if err != nil {
+		return "", nil, err
+	}
+	pkg, err := goskills.ParseSkillPackage(skillPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse skill: %w", err)
+	}
+	sig, err := goskills.VerifySkill(pkg, keys)
+	return skillPath, sig, err
+}
+
+func init() {
+	keygenCmd.Flags().StringP("comment", "c", "", "Comment to store with the public key, such as the signer's email")
+	signCmd.Flags().StringP("key", "k", "", "Private key file made by keygen")
+	verifyCmd.Flags().String("trusted-keys", goskills.DefaultTrustedKeysFile(), "File listing the public keys whose signatures are trusted")
+	rootCmd.AddCommand(keygenCmd, signCmd, verifyCmd)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <path>...",
+	Short: "Validates skill packages against the Agent Skills spec.",
+	Long: `The validate command checks each skill found under the given paths against the
+Agent Skills spec and reports problems with their rule ID and location.
+
+A path may be a single skill directory or a directory containing several skills.
+The command exits with a non-zero status if any error is found, so it can be used
+to gate skill repositories in review. Use --format json or --format sarif to
+produce machine-readable output.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+		maxFileSize, err := cmd.Flags().GetInt64("max-file-size")
+		if err != nil {
+			return err
+		}
+		maxTotalSize, err := cmd.Flags().GetInt64("max-total-size")
+		if err != nil {
+			return err
+		}
+		symlinks, err := cmd.Flags().GetString("symlinks")
+		if err != nil {
+			return err
+		}
+		policy, err := goskills.ParseSymlinkPolicy(symlinks)
+		if err != nil {
+			return err
+		}
+		opts := []goskills.ParseOption{
+			goskills.WithMaxFileSize(maxFileSize),
+			goskills.WithMaxTotalSize(maxTotalSize),
+			goskills.WithSymlinkPolicy(policy),
+		}
+
+		var diags []goskills.Diagnostic
+		skillCount := 0
+		for _, root := range args {
+			result, err := goskills.ScanSkillPackages(root, opts...)
+			if err != nil {
+				return fmt.Errorf("could not scan '%s': %w", root, err)
+			}
+			for _, failure := range result.Failures {
+				diags = append(diags, goskills.ParseErrorDiagnostic(failure.Dir, failure.Err))
+			}
+			for _, pkg := range result.Packages {
+				diags = append(diags, goskills.Validate(pkg)...)
+			}
+			skillCount += len(result.Packages) + len(result.Failures)
+		}
+
+		out := cmd.OutOrStdout()
+		switch format {
+		case "text":
+			writeDiagnosticsText(out, diags, skillCount)
+		case "json":
+			err = writeDiagnosticsJSON(out, diags)
+		case "sarif":
+			err = writeDiagnosticsSARIF(out, diags)
+		default:
+			return fmt.Errorf("unknown format '%s' (expected text, json or sarif)", format)
+		}
+		if err != nil {
+			return err
+		}
+
+		failed := goskills.HasErrors(diags) || (strict && len(diags) > 0)
+		if failed {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("validation failed")
+		}
+		return nil
+	},
+}
+
+func writeDiagnosticsText(w io.Writer, diags []goskills.Diagnostic, skillCount int) {
+	errors, warnings := 0, 0
+	for _, d := range diags {
+		fmt.Fprintln(w, d.String())
+		switch d.Severity {
+		case goskills.SeverityError:
+			errors++
+		case goskills.SeverityWarning:
+			warnings++
+		}
+	}
+	fmt.Fprintf(w, "Validated %d skill(s): %d error(s), %d warning(s).\n", skillCount, errors, warnings)
+}
+
+func writeDiagnosticsJSON(w io.Writer, diags []goskills.Diagnostic) error {
+	if diags == nil {
+		diags = []goskills.Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diags)
+}
+
+// The types below model the subset of SARIF 2.1.0 needed to report diagnostics.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func writeDiagnosticsSARIF(w io.Writer, diags []goskills.Diagnostic) error {
+	var rules []sarifRule
+	for _, id := range goskills.Rules() {
+		rules = append(rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: goskills.RuleDescription(id)}})
+	}
+
+	results := []sarifResult{}
+	for _, d := range diags {
+		level := "note"
+		switch d.Severity {
+		case goskills.SeverityError:
+			level = "error"
+		case goskills.SeverityWarning:
+			level = "warning"
+		}
+
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(d.File)}}
+		if d.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+		}
+		results = append(results, sarifResult{
+			RuleID:    d.Rule,
+			Level:     level,
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "goskills-cli",
+				InformationURI: "https://github.com/smallnest/goskills",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifURI converts a file path into a URI relative to the working directory when possible.
+func sarifURI(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel)
+			}
+			path = abs
+		}
+	}
+	return "file://" + filepath.ToSlash(path)
+}
+
+func init() {
+	validateCmd.Flags().StringP("format", "f", "text", "Output format: text, json or sarif")
+	validateCmd.Flags().Bool("strict", false, "Treat warnings as errors")
+	validateCmd.Flags().Int64("max-file-size", 0, "Warn about and leave out files larger than this many bytes (0 for no limit)")
+	validateCmd.Flags().Int64("max-total-size", 0, "Warn about skills whose files add up to more than this many bytes (0 for no limit)")
+	validateCmd.Flags().String("symlinks", string(goskills.SymlinkMark), "Handling of symbolic links leaving a skill: allow, mark, skip or reject")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	openai "github.com/sashabaranov/go-openai"
 	"github.com/smallnest/goskills"
@@ -17,6 +19,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// scriptHelpTimeout bounds each script run made by --capture-script-help.
+const scriptHelpTimeout = 5 * time.Second
+
 var runCmd = &cobra.Command{
 	Use:   "run [prompt]",
 	Short: "Processes a user request by selecting and executing a skill.",
@@ -63,9 +68,9 @@ You can specify a custom model and API base URL using flags.`,
 
 		// --- STEP 1: SKILL DISCOVERY ---
 		if cfg.Verbose {
-			fmt.Printf("🔎 Discovering available skills in %s...\n", cfg.SkillsDir)
+			fmt.Printf("🔎 Discovering available skills in %s...\n", strings.Join(cfg.SkillsDirs, ", "))
 		}
-		availableSkills, err := discoverSkills(cfg.SkillsDir)
+		registry, availableSkills, err := discoverSkills(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to discover skills: %w", err)
 		}
@@ -81,18 +86,36 @@ You can specify a custom model and API base URL using flags.`,
 			return fmt.Errorf("failed during skill selection: %w", err)
 		}
 
-		selectedSkill, ok := availableSkills[selectedSkillName]
+		selectedIndex, ok := availableSkills[selectedSkillName]
 		if !ok {
 			fmt.Printf("⚠️ LLM selected a non-existent skill '%s'. Aborting.\n", selectedSkillName)
 			return nil
 		}
 		fmt.Printf("✅ LLM selected skill: %s\n\n", selectedSkillName)
 
+		// Only the selected skill's body and resources are loaded.
+		selectedSkill, err := selectedIndex.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load skill '%s': %w", selectedSkillName, err)
+		}
+		if cfg.Verbose {
+			for _, w := range selectedSkill.Warnings {
+				fmt.Printf("⚠️ %s\n", w)
+			}
+		}
+		if err := checkSignature(cfg, selectedSkill); err != nil {
+			return err
+		}
+		dependencies, err := loadDependencies(cfg, registry, selectedSkillName)
+		if err != nil {
+			return err
+		}
+
 		// --- STEP 3: SKILL EXECUTION (with Tool Calling) ---
 		fmt.Println("🚀 Executing skill (with potential tool calls)...")
 		fmt.Println(strings.Repeat("-", 40))
 
-		err = executeSkillWithTools(ctx, client, cfg, userPrompt, selectedSkill)
+		err = executeSkillWithTools(ctx, client, cfg, userPrompt, *selectedSkill, dependencies)
 		if err != nil {
 			return fmt.Errorf("failed during skill execution: %w", err)
 		}
@@ -101,28 +124,117 @@ You can specify a custom model and API base URL using flags.`,
 	},
 }
 
-func discoverSkills(skillsRoot string) (map[string]goskills.SkillPackage, error) {
-	packages, err := goskills.ParseSkillPackages(skillsRoot)
+// discoverSkills indexes the available skills, reading only their frontmatter.
+// The --skills-dir roots take precedence over the default project, user and
+// system roots; when several skills share a name, the one in the
+// highest-precedence root is used. It returns the registry along with the
+// active skills by name.
+func discoverSkills(cfg *config.Config) (*goskills.Registry, map[string]*goskills.SkillIndex, error) {
+	var roots []goskills.Root
+	for _, dir := range cfg.SkillsDirs {
+		roots = append(roots, goskills.Root{Path: dir, Scope: "flag"})
+	}
+	roots = append(roots, goskills.DefaultRoots(".")...)
+
+	registry, err := goskills.NewRegistry(roots, goskills.WithMaxFileSize(cfg.MaxFileSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, failure := range registry.Failures() {
+		fmt.Printf("⚠️ Skipping skill in %s: %v\n", failure.Dir, failure.Err)
+	}
+	for _, c := range registry.Collisions() {
+		for _, hidden := range c.Hidden {
+			switch {
+			case hidden.Root == c.Active.Root:
+				fmt.Printf("⚠️ Skill '%s' in %s has the same name as %s; ignoring it\n", c.Name, hidden.Path, c.Active.Path)
+			case cfg.Verbose:
+				fmt.Printf("ℹ️ Skill '%s' in %s is shadowed by %s\n", c.Name, hidden.Path, c.Active.Path)
+			}
+		}
+	}
+
+	skills := make(map[string]*goskills.SkillIndex, len(registry.Skills()))
+	for _, s := range registry.Skills() {
+		skills[s.Meta.Name] = s.SkillIndex
+	}
+
+	return registry, skills, nil
+}
+
+// loadDependencies loads the skills that the selected skill requires,
+// directly or indirectly, in the order their context is given to the model.
+// Unsatisfied requirements are reported and skipped.
+func loadDependencies(cfg *config.Config, registry *goskills.Registry, name string) ([]*goskills.SkillPackage, error) {
+	deps, problems, err := registry.Dependencies(name)
 	if err != nil {
 		return nil, err
 	}
+	for _, p := range problems {
+		fmt.Printf("⚠️ %s\n", p)
+	}
 
-	skills := make(map[string]goskills.SkillPackage, len(packages))
-	for _, pkg := range packages {
-		if pkg != nil {
-			skills[pkg.Meta.Name] = *pkg
+	var pkgs []*goskills.SkillPackage
+	for _, dep := range deps {
+		pkg, err := registry.Load(dep.Meta.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load skill '%s' required by '%s': %w", dep.Meta.Name, name, err)
+		}
+		if err := checkSignature(cfg, pkg); err != nil {
+			return nil, err
 		}
+		if cfg.Verbose {
+			fmt.Printf("📎 Including required skill: %s\n", dep.Meta.Name)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// checkSignature applies the --require-signature mode to the selected skill.
+// A skill without a valid signature by a trusted key is refused in "all"
+// mode; in "scripts" mode its scripts are removed from its inventory, so none
+// becomes a tool or is run to capture its --help.
+func checkSignature(cfg *config.Config, skill *goskills.SkillPackage) error {
+	if cfg.RequireSignature == config.SignatureOff {
+		return nil
+	}
+	keysFile := cfg.TrustedKeysFile
+	if keysFile == "" {
+		keysFile = goskills.DefaultTrustedKeysFile()
+	}
+	keys, err := goskills.ReadTrustedKeys(keysFile)
+	if err != nil {
+		return fmt.Errorf("failed to read trusted keys: %w", err)
 	}
 
-	return skills, nil
+	sig, err := goskills.VerifySkill(skill, keys)
+	switch {
+	case err == nil:
+		if cfg.Verbose {
+			fmt.Printf("🔏 Skill '%s' is signed by trusted key %s\n", skill.Meta.Name, sig.KeyID)
+		}
+	case cfg.RequireSignature == config.SignatureAll:
+		return fmt.Errorf("refusing to load skill '%s': %w", skill.Meta.Name, err)
+	default:
+		fmt.Printf("⚠️ Not exposing the scripts of skill '%s': %v\n", skill.Meta.Name, err)
+		skill.Resources = skill.Resources.Without(goskills.KindScript)
+	}
+	return nil
 }
 
-func selectSkill(ctx context.Context, client *openai.Client, cfg *config.Config, userPrompt string, skills map[string]goskills.SkillPackage) (string, error) {
+func selectSkill(ctx context.Context, client *openai.Client, cfg *config.Config, userPrompt string, skills map[string]*goskills.SkillIndex) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("User Request: " + "" + userPrompt + "" + "\n\n")
 	sb.WriteString("Available Skills:\n")
-	for name, skill := range skills {
-		sb.WriteString(fmt.Sprintf("- %s: %s\n", name, skill.Meta.Description))
+	// Sort names so the catalog, and therefore the selection prompt, is reproducible.
+	names := make([]string, 0, len(skills))
+	for name := range skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", name, skills[name].Meta.Description))
 	}
 	sb.WriteString("\nBased on the user request, which single skill is the most appropriate to use? Respond with only the name of the skill.")
 
@@ -154,111 +266,27 @@ func selectSkill(ctx context.Context, client *openai.Client, cfg *config.Config,
 }
 
 // executeToolCall executes a single tool call and returns its output.
-func executeToolCall(toolCall openai.ToolCall, scriptMap map[string]string, skillPath string) (string, error) {
-	var toolOutput string
-	var err error
-
-	switch toolCall.Function.Name {
-	case "run_shell_script":
-		var params struct {
-			ScriptPath string   `json:"scriptPath"`
-			Args       []string `json:"args"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-			return "", fmt.Errorf("failed to unmarshal run_shell_script arguments: %w", err)
-		}
-		toolOutput, err = tool.RunShellScript(params.ScriptPath, params.Args)
-	case "run_python_script":
-		var params struct {
-			ScriptPath string   `json:"scriptPath"`
-			Args       []string `json:"args"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-			return "", fmt.Errorf("failed to unmarshal run_python_script arguments: %w", err)
-		}
-		toolOutput, err = tool.RunPythonScript(params.ScriptPath, params.Args)
-	case "read_file":
-		var params struct {
-			FilePath string `json:"filePath"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-			return "", fmt.Errorf("failed to unmarshal read_file arguments: %w", err)
-		}
-
-		// Resolve path relative to skill directory if it's not absolute and skillPath is provided
-		path := params.FilePath
-		if !filepath.IsAbs(path) && skillPath != "" {
-			resolvedPath := filepath.Join(skillPath, path)
-			// Check if file exists at resolved path
-			if _, err := os.Stat(resolvedPath); err == nil {
-				path = resolvedPath
-			}
-		}
-
-		toolOutput, err = tool.ReadFile(path)
-	case "write_file":
-		var params struct {
-			FilePath string `json:"filePath"`
-			Content  string `json:"content"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-			return "", fmt.Errorf("failed to unmarshal write_file arguments: %w", err)
-		}
-		err = tool.WriteFile(params.FilePath, params.Content)
-		if err == nil {
-			toolOutput = fmt.Sprintf("Successfully wrote to file: %s", params.FilePath)
-		}
-	case "duckduckgo_search":
-		var params struct {
-			Query string `json:"query"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-			return "", fmt.Errorf("failed to unmarshal duckduckgo_search arguments: %w", err)
-		}
-		toolOutput, err = tool.DuckDuckGoSearch(params.Query)
-	case "wikipedia_search":
-		var params struct {
-			Query string `json:"query"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-			return "", fmt.Errorf("failed to unmarshal wikipedia_search arguments: %w", err)
-		}
-		toolOutput, err = tool.WikipediaSearch(params.Query)
-	default:
-		// Check if it's a generated script tool
-		if scriptPath, ok := scriptMap[toolCall.Function.Name]; ok {
-			var params struct {
-				Args []string `json:"args"`
-			}
-			// Arguments might be optional or empty
-			if toolCall.Function.Arguments != "" {
-				if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
-					return "", fmt.Errorf("failed to unmarshal script arguments: %w", err)
-				}
-			}
-
-			// Determine if python or shell based on extension
-			if strings.HasSuffix(scriptPath, ".py") {
-				toolOutput, err = tool.RunPythonScript(scriptPath, params.Args)
-			} else {
-				toolOutput, err = tool.RunShellScript(scriptPath, params.Args)
-			}
-		} else {
-			return "", fmt.Errorf("unknown tool: %s", toolCall.Function.Name)
-		}
-	}
-
+func executeToolCall(ctx context.Context, registry *tool.Registry, toolCall openai.ToolCall) (string, error) {
+	result, err := registry.Execute(ctx, toolCall.Function.Name, json.RawMessage(toolCall.Function.Arguments))
 	if err != nil {
 		return "", fmt.Errorf("tool execution failed for %s: %w", toolCall.Function.Name, err)
 	}
-	return toolOutput, nil
+	return result.Output, nil
 }
 
 // executeSkillWithTools executes a skill, handling potential tool calls in a loop.
-func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *config.Config, userPrompt string, skill goskills.SkillPackage) error {
+// The instructions of the skills it requires are added to its context.
+func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *config.Config, userPrompt string, skill goskills.SkillPackage, dependencies []*goskills.SkillPackage) error {
 	// Reconstruct the skill body from structured parts for the system prompt
 	var skillBody strings.Builder
-	skillBody.WriteString(skill.Body) // Directly use the raw markdown body
+	body := skill.Body // Directly use the raw markdown body
+	if cfg.MaxBodyChars > 0 && skill.Document != nil {
+		body = skill.Document.Excerpt(userPrompt, cfg.MaxBodyChars)
+		if body != skill.Body {
+			fmt.Printf("✂️  Skill body shortened to %d of %d characters\n", utf8.RuneCountInString(body), utf8.RuneCountInString(skill.Body))
+		}
+	}
+	skillBody.WriteString(body)
 	skillBody.WriteString("\n\n")
 
 	// --- INJECT SKILL CONTEXT ---
@@ -277,10 +305,31 @@ func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *conf
 			skillBody.WriteString(fmt.Sprintf("  - %s\n", t))
 		}
 	}
+	interpreters, err := cfg.ScriptInterpreters()
+	if err != nil {
+		return err
+	}
+	toolOpts := []goskills.ToolOption{goskills.WithInterpreters(interpreters)}
+	if cfg.ScriptHelp {
+		toolOpts = append(toolOpts, goskills.WithHelpCapture(scriptHelpTimeout))
+	}
+
+	var refTools []*goskills.ReferenceTool
+	if cfg.Progressive {
+		refTools = goskills.ReferenceTools(skill, toolOpts...)
+	}
 	if len(skill.Resources.References) > 0 {
 		skillBody.WriteString("- References:\n")
+		refTool := make(map[string]string, len(refTools))
+		for _, t := range refTools {
+			refTool[t.Path] = t.Name()
+		}
 		for _, r := range skill.Resources.References {
-			skillBody.WriteString(fmt.Sprintf("  - %s\n", r))
+			if name, ok := refTool[r]; ok {
+				skillBody.WriteString(fmt.Sprintf("  - %s (load on demand with the '%s' tool)\n", r, name))
+			} else {
+				skillBody.WriteString(fmt.Sprintf("  - %s\n", r))
+			}
 		}
 	}
 	if len(skill.Resources.Assets) > 0 {
@@ -289,8 +338,39 @@ func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *conf
 			skillBody.WriteString(fmt.Sprintf("  - %s\n", a))
 		}
 	}
+	var otherFiles []string
+	for _, f := range skill.Resources.Files {
+		if f.Kind == goskills.KindLicense || f.Kind == goskills.KindOther {
+			otherFiles = append(otherFiles, f.Path)
+		}
+	}
+	if len(otherFiles) > 0 {
+		skillBody.WriteString("- Other Files:\n")
+		for _, o := range otherFiles {
+			skillBody.WriteString(fmt.Sprintf("  - %s\n", o))
+		}
+	}
+	if referenced := skill.ReferencedFiles(); len(referenced) > 0 {
+		skillBody.WriteString("Files referenced by the instructions above (read them when the task needs them):\n")
+		for _, r := range referenced {
+			skillBody.WriteString(fmt.Sprintf("- %s\n", r))
+		}
+	}
 	skillBody.WriteString("\nIMPORTANT: When reading resource files mentioned in the skill definition, you must use the full path or a path relative to the Skill Root Path.\n")
 
+	// --- INJECT DEPENDENCY CONTEXT ---
+	for _, dep := range dependencies {
+		depBody := dep.Body
+		if cfg.MaxBodyChars > 0 && dep.Document != nil {
+			depBody = dep.Document.Excerpt(userPrompt, cfg.MaxBodyChars)
+		}
+		skillBody.WriteString(fmt.Sprintf("\n## DEPENDENCY: %s\n", dep.Meta.Name))
+		skillBody.WriteString(fmt.Sprintf("The skill above builds on the '%s' skill. Its instructions follow; resolve its files against its own root path.\n", dep.Meta.Name))
+		skillBody.WriteString(fmt.Sprintf("Skill Root Path: %s\n\n", dep.Path))
+		skillBody.WriteString(depBody)
+		skillBody.WriteString("\n")
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
@@ -302,7 +382,20 @@ func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *conf
 		},
 	}
 
-	availableTools, scriptMap := goskills.GenerateToolDefinitions(skill)
+	policy, err := goskills.NewToolPolicy(skill.Meta.AllowedTools, skill.Meta.DisallowedTools)
+	if err != nil {
+		fmt.Printf("⚠️ Ignoring invalid tool rules: %v\n", err)
+	}
+	registry := goskills.SkillTools(skill, toolOpts...)
+	for _, t := range refTools {
+		if err := registry.Register(t); err != nil {
+			return err
+		}
+	}
+	for _, c := range goskills.ToolNameCollisions(skill, toolOpts...) {
+		fmt.Printf("⚠️ %s\n", c)
+	}
+	availableTools := registry.Definitions()
 
 	// --- DEBUG: Print Available Tools ---
 	fmt.Println("🛠️  Available Tools:")
@@ -384,7 +477,22 @@ func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *conf
 				fmt.Printf("⚙️ Calling tool: %s with args: %s\n", tc.Function.Name, tc.Function.Arguments)
 
 				// --- SECURITY CHECK ---
-				// 1. Allowlist Check
+				// 1. Skill Policy Check (allowed-tools and disallowed-tools)
+				command := tc.Function.Arguments
+				if t, ok := registry.Lookup(tc.Function.Name); ok {
+					command = goskills.ToolCommand(t, tc.Function.Arguments)
+				}
+				if err := policy.Check(tc.Function.Name, command); err != nil {
+					fmt.Printf("❌ Tool execution denied: %v.\n", err)
+					messages = append(messages, openai.ChatCompletionMessage{
+						Role:       openai.ChatMessageRoleTool,
+						ToolCallID: tc.ID,
+						Content:    fmt.Sprintf("Error: %v.", err),
+					})
+					continue
+				}
+
+				// 2. Allowlist Check
 				if len(cfg.AllowedScripts) > 0 {
 					allowed := false
 					for _, script := range cfg.AllowedScripts {
@@ -404,7 +512,7 @@ func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *conf
 					}
 				}
 
-				// 2. Confirmation Prompt
+				// 3. Confirmation Prompt
 				if !cfg.AutoApproveTools {
 					fmt.Print("⚠️  Allow this tool execution? [y/N]: ")
 					var input string
@@ -420,7 +528,7 @@ func executeSkillWithTools(ctx context.Context, client *openai.Client, cfg *conf
 					}
 				}
 
-				toolOutput, err := executeToolCall(tc, scriptMap, skill.Path)
+				toolOutput, err := executeToolCall(ctx, registry, tc)
 				if err != nil {
 					fmt.Printf("❌ Tool call failed: %v\n", err)
 					// Add error message to history and let LLM try to recover
@@ -1,22 +1,38 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/smallnest/goskills/tool"
 	"github.com/spf13/cobra"
 )
 
+// Modes of Config.RequireSignature.
+const (
+	SignatureOff     = "off"     // Skills are used whether or not they are signed
+	SignatureScripts = "scripts" // Scripts of skills without a valid signature are not exposed as tools
+	SignatureAll     = "all"     // Skills without a valid signature are not loaded
+)
+
 // Config holds the application configuration
 type Config struct {
-	SkillsDir        string
+	SkillsDirs       []string // Skill roots given on the command line, in order of precedence
 	Model            string
 	APIBase          string
 	APIKey           string
 	AutoApproveTools bool
 	AllowedScripts   []string
 	Verbose          bool
+	Progressive      bool     // Expose reference documents as on-demand tools
+	MaxBodyChars     int      // Budget for the skill body in the system prompt; 0 means unlimited
+	MaxFileSize      int64    // Skill files larger than this many bytes are not offered to the model; 0 means unlimited
+	RequireSignature string   // One of SignatureOff, SignatureScripts or SignatureAll
+	TrustedKeysFile  string   // File listing the public keys trusted to sign skills; empty for the default
+	ScriptHelp       bool     // Run argparse scripts with --help to learn their parameters
+	Interpreters     []string // Interpreter mappings ".EXT=COMMAND" or "PROGRAM=COMMAND" applied over the defaults
 }
 
 // LoadConfig loads configuration from flags and environment variables
@@ -25,7 +41,7 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 
 	// 1. Load from flags (if set)
 	var err error
-	cfg.SkillsDir, err = cmd.Flags().GetString("skills-dir")
+	cfg.SkillsDirs, err = cmd.Flags().GetStringSlice("skills-dir")
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +65,42 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg.Progressive, err = cmd.Flags().GetBool("progressive")
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxBodyChars, err = cmd.Flags().GetInt("max-body-chars")
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxFileSize, err = cmd.Flags().GetInt64("max-file-size")
+	if err != nil {
+		return nil, err
+	}
+	cfg.RequireSignature, err = cmd.Flags().GetString("require-signature")
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.RequireSignature {
+	case SignatureOff, SignatureScripts, SignatureAll:
+	default:
+		return nil, fmt.Errorf("unknown --require-signature mode '%s' (expected off, scripts or all)", cfg.RequireSignature)
+	}
+	cfg.TrustedKeysFile, err = cmd.Flags().GetString("trusted-keys")
+	if err != nil {
+		return nil, err
+	}
+	cfg.ScriptHelp, err = cmd.Flags().GetBool("capture-script-help")
+	if err != nil {
+		return nil, err
+	}
+	cfg.Interpreters, err = cmd.Flags().GetStringArray("interpreter")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cfg.ScriptInterpreters(); err != nil {
+		return nil, err
+	}
 
 	// 2. Load from environment variables (fallback if flag not set or empty, except bools)
 	// Note: Cobra flags usually handle defaults, but we check env vars here for precedence if needed
@@ -66,25 +118,46 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 	}
 	cfg.APIBase = strings.TrimSuffix(cfg.APIBase, "/")
 
-	// Resolve SkillsDir to absolute path
-	if cfg.SkillsDir == "" {
-		cfg.SkillsDir = "./examples/skills" // Default
+	// Resolve SkillsDirs to absolute paths
+	if len(cfg.SkillsDirs) == 0 {
+		cfg.SkillsDirs = []string{"./examples/skills"} // Default
 	}
-	absSkillsDir, err := filepath.Abs(cfg.SkillsDir)
-	if err != nil {
-		return nil, err
+	for i, dir := range cfg.SkillsDirs {
+		absSkillsDir, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SkillsDirs[i] = absSkillsDir
 	}
-	cfg.SkillsDir = absSkillsDir
 
 	return cfg, nil
 }
 
 // SetupFlags registers the flags with the command
 func SetupFlags(cmd *cobra.Command) {
-	cmd.Flags().StringP("skills-dir", "d", "./examples/skills", "Path to the skills directory")
+	cmd.Flags().StringSliceP("skills-dir", "d", []string{"./examples/skills"}, "Skills directories, highest precedence first; searched before the project, user and system roots")
 	cmd.Flags().StringP("model", "m", "", "OpenAI-compatible model name")
 	cmd.Flags().StringP("api-base", "b", "", "OpenAI-compatible API base URL")
 	cmd.Flags().Bool("auto-approve", false, "Auto-approve all tool calls (WARNING: potentially unsafe)")
 	cmd.Flags().StringSlice("allow-scripts", nil, "Comma-separated list of allowed script names (e.g. 'run_myscript_py')")
 	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	cmd.Flags().Bool("progressive", false, "Load reference documents on demand through tools instead of listing them only")
+	cmd.Flags().Int("max-body-chars", 0, "Maximum characters of the skill body to send; when exceeded, only the sections most relevant to the request are included (0 for no limit)")
+	cmd.Flags().Int64("max-file-size", 0, "Leave skill files larger than this many bytes out of the skill context (0 for no limit)")
+	cmd.Flags().String("require-signature", SignatureOff, "Signature check for the selected skill: off, scripts (hide the scripts of unverified skills) or all (refuse unverified skills)")
+	cmd.Flags().String("trusted-keys", "", "File listing the public keys trusted to sign skills (default ~/.config/goskills/trusted_keys)")
+	cmd.Flags().Bool("capture-script-help", false, "Run Python scripts that use argparse and declare no parameters with --help to learn them")
+	cmd.Flags().StringArray("interpreter", nil, "Run scripts with an extension or shebang program with a command, as '.EXT=COMMAND' or 'PROGRAM=COMMAND' (e.g. '.ts=deno run'); an empty COMMAND stops exposing them (repeatable)")
+}
+
+// ScriptInterpreters returns the default script interpreters with the
+// --interpreter mappings applied.
+func (c *Config) ScriptInterpreters() (*tool.Interpreters, error) {
+	interpreters := tool.DefaultInterpreters()
+	for _, mapping := range c.Interpreters {
+		if err := interpreters.Set(mapping); err != nil {
+			return nil, fmt.Errorf("--interpreter: %w", err)
+		}
+	}
+	return interpreters, nil
 }
@@ -0,0 +1,201 @@
+package goskills
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dependency is an entry of a skill's 'requires' list: another skill whose
+// instructions it builds on, and the versions of it that are acceptable. In
+// frontmatter it is written either as a string, "ooxml" or "ooxml ^1.2", or
+// as a mapping with 'name' and 'version' keys.
+type Dependency struct {
+	Name    string `yaml:"name" json:"name"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"` // Constraint in ParseConstraint syntax; empty accepts any version
+}
+
+// UnmarshalYAML accepts the string and mapping forms of a dependency.
+// The constraint is checked by Validate, not here, so that a bad constraint
+// does not keep the skill from loading.
+func (d *Dependency) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		name, version, _ := strings.Cut(strings.TrimSpace(node.Value), " ")
+		*d = Dependency{Name: name, Version: strings.TrimSpace(version)}
+		return nil
+	case yaml.MappingNode:
+		type plain Dependency
+		return node.Decode((*plain)(d))
+	}
+	return fmt.Errorf("line %d: a dependency must be a skill name or a mapping with 'name' and 'version'", node.Line)
+}
+
+// MarshalYAML writes a dependency in its string form.
+func (d Dependency) MarshalYAML() (interface{}, error) {
+	if d.Version == "" {
+		return d.Name, nil
+	}
+	return d.Name + " " + d.Version, nil
+}
+
+// String returns the dependency in its frontmatter string form.
+func (d Dependency) String() string {
+	if d.Version == "" {
+		return d.Name
+	}
+	return d.Name + " " + d.Version
+}
+
+// Constraint parses the dependency's version constraint.
+func (d Dependency) Constraint() (*Constraint, error) {
+	return ParseConstraint(d.Version)
+}
+
+// Satisfied reports whether version satisfies the dependency's constraint.
+// Any version, even an empty one, satisfies a dependency without a
+// constraint; otherwise the version must be a semantic version.
+func (d Dependency) Satisfied(version string) (bool, error) {
+	if strings.TrimSpace(d.Version) == "" {
+		return true, nil
+	}
+	c, err := d.Constraint()
+	if err != nil {
+		return false, err
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, nil
+	}
+	return c.Check(v), nil
+}
+
+// Dependency problem kinds.
+const (
+	DependencyMissing  = "missing"  // No active skill has the required name
+	DependencyConflict = "conflict" // The active skill's version does not satisfy the constraint
+	DependencyInvalid  = "invalid"  // The constraint does not parse
+	DependencyCycle    = "cycle"    // The skill depends on itself, directly or through others
+)
+
+// DependencyProblem is a 'requires' entry of an active skill that cannot be
+// satisfied by the registry.
+type DependencyProblem struct {
+	Skill    string           `json:"skill"` // Name of the skill declaring the dependency
+	Kind     string           `json:"kind"`  // DependencyMissing, DependencyConflict, DependencyInvalid or DependencyCycle
+	Requires Dependency       `json:"requires"`
+	Found    *RegisteredSkill `json:"found,omitempty"` // The active skill of the required name, if any
+	Cycle    []string         `json:"cycle,omitempty"` // For DependencyCycle, the names along the cycle, starting and ending with Skill
+	Err      string           `json:"error,omitempty"` // For DependencyInvalid, why the constraint does not parse
+}
+
+func (p DependencyProblem) String() string {
+	switch p.Kind {
+	case DependencyMissing:
+		return fmt.Sprintf("skill '%s' requires '%s', which is not installed", p.Skill, p.Requires)
+	case DependencyConflict:
+		version := p.Found.Meta.Version
+		if version == "" {
+			version = "without a version"
+		} else {
+			version = "version " + version
+		}
+		return fmt.Sprintf("skill '%s' requires '%s', but %s is %s", p.Skill, p.Requires, p.Found.Path, version)
+	case DependencyInvalid:
+		return fmt.Sprintf("skill '%s' requires '%s': %s", p.Skill, p.Requires, p.Err)
+	case DependencyCycle:
+		return fmt.Sprintf("skill '%s' has a dependency cycle: %s", p.Skill, strings.Join(p.Cycle, " -> "))
+	}
+	return fmt.Sprintf("skill '%s' requires '%s': %s", p.Skill, p.Requires, p.Kind)
+}
+
+// Dependencies returns the skills that the active skill with the given name
+// requires, directly or indirectly, ordered so that every skill comes after
+// the skills it requires. Requirements that cannot be satisfied are left out
+// and reported as problems; a skill whose version conflicts is left out too.
+// It fails only if no active skill has the name.
+func (r *Registry) Dependencies(name string) ([]*RegisteredSkill, []DependencyProblem, error) {
+	s, ok := r.Lookup(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("skill not found: %s", name)
+	}
+	res := newDependencyResolver(r)
+	res.visit(s, nil)
+	return res.order[:len(res.order)-1], res.problems, nil
+}
+
+// DependencyProblems checks the 'requires' lists of all active skills and
+// returns the problems found, sorted by skill name. A cycle is reported once,
+// for the skill where it was detected.
+func (r *Registry) DependencyProblems() []DependencyProblem {
+	res := newDependencyResolver(r)
+	skills := r.Skills()
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Meta.Name < skills[j].Meta.Name })
+	for _, s := range skills {
+		res.visit(s, nil)
+	}
+	sort.SliceStable(res.problems, func(i, j int) bool { return res.problems[i].Skill < res.problems[j].Skill })
+	return res.problems
+}
+
+// dependencyResolver walks the dependency graph of a registry depth first.
+type dependencyResolver struct {
+	r        *Registry
+	done     map[string]bool // Skills whose dependencies have all been visited
+	order    []*RegisteredSkill
+	problems []DependencyProblem
+}
+
+func newDependencyResolver(r *Registry) *dependencyResolver {
+	return &dependencyResolver{r: r, done: make(map[string]bool)}
+}
+
+// visit appends the dependencies of s, then s itself, to the order. path
+// holds the names of the skills being visited, to detect cycles.
+func (res *dependencyResolver) visit(s *RegisteredSkill, path []string) {
+	name := s.Meta.Name
+	if res.done[name] {
+		return
+	}
+	path = append(path, name)
+	for _, dep := range s.Meta.Requires {
+		if i := indexOf(path, dep.Name); i >= 0 {
+			res.problems = append(res.problems, DependencyProblem{
+				Skill:    name,
+				Kind:     DependencyCycle,
+				Requires: dep,
+				Cycle:    append(append([]string(nil), path[i:]...), dep.Name),
+			})
+			continue
+		}
+		found, ok := res.r.Lookup(dep.Name)
+		if !ok {
+			res.problems = append(res.problems, DependencyProblem{Skill: name, Kind: DependencyMissing, Requires: dep})
+			continue
+		}
+		satisfied, err := dep.Satisfied(found.Meta.Version)
+		switch {
+		case err != nil:
+			res.problems = append(res.problems, DependencyProblem{Skill: name, Kind: DependencyInvalid, Requires: dep, Found: found, Err: err.Error()})
+			continue
+		case !satisfied:
+			res.problems = append(res.problems, DependencyProblem{Skill: name, Kind: DependencyConflict, Requires: dep, Found: found})
+			continue
+		}
+		res.visit(found, path)
+	}
+	res.done[name] = true
+	res.order = append(res.order, s)
+}
+
+// indexOf returns the index of the first occurrence of s in list, or -1.
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,156 @@
+package goskills
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontmatterError reports a problem locating or decoding the frontmatter of a SKILL.md file.
+type FrontmatterError struct {
+	Line int    // 1-based line in SKILL.md where the problem was detected
+	Msg  string // Description of the problem
+	Err  error  // Underlying YAML error, if any
+}
+
+func (e *FrontmatterError) Error() string {
+	msg := fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *FrontmatterError) Unwrap() error {
+	return e.Err
+}
+
+// utf8BOM is stripped from the start of SKILL.md if present.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages.
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// skillFile holds the parsed parts of a SKILL.md file.
+type skillFile struct {
+	meta     SkillMeta
+	node     *yaml.Node // Frontmatter mapping, nil if the frontmatter is empty
+	body     string     // Body with surrounding whitespace trimmed and line endings normalized
+	bodyLine int        // Line of SKILL.md on which the trimmed body starts
+}
+
+// readFrontmatter consumes the frontmatter block from r, leaving r positioned at
+// the first line of the body. The opening "---" must be on line 1 (optionally
+// preceded by a UTF-8 BOM); the block is closed by a line containing only "---"
+// or "...". The returned YAML is padded with one leading newline so that line
+// numbers reported by the YAML decoder match lines in SKILL.md. next is the
+// line number of the first body line.
+func readFrontmatter(r *bufio.Reader) (fm []byte, next int, err error) {
+	first, err := readLine(r)
+	if err != nil && (err != io.EOF || first == "") {
+		if err == io.EOF {
+			return nil, 0, &FrontmatterError{Line: 1, Msg: "no YAML frontmatter found: SKILL.md is empty"}
+		}
+		return nil, 0, err
+	}
+	first = strings.TrimPrefix(first, string(utf8BOM))
+	if strings.TrimRight(first, " \t") != "---" {
+		return nil, 0, &FrontmatterError{Line: 1, Msg: "no YAML frontmatter found: SKILL.md must start with a '---' line"}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('\n')
+	line := 1
+	for {
+		text, err := readLine(r)
+		if text == "" && err == io.EOF {
+			return nil, 0, &FrontmatterError{Line: 1, Msg: "unterminated YAML frontmatter: no closing '---' or '...' line found"}
+		}
+		if err != nil && err != io.EOF {
+			return nil, 0, err
+		}
+		line++
+
+		if trimmed := strings.TrimRight(text, " \t"); trimmed == "---" || trimmed == "..." {
+			return buf.Bytes(), line + 1, nil
+		}
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+	}
+}
+
+// readLine reads a single line from r without its line terminator.
+// A trailing "\r" from CRLF line endings is removed.
+func readLine(r *bufio.Reader) (string, error) {
+	text, err := r.ReadString('\n')
+	text = strings.TrimSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\r")
+	return text, err
+}
+
+// decodeFrontmatter decodes the YAML produced by readFrontmatter into meta.
+// It returns the frontmatter mapping node, or nil if the frontmatter is empty.
+func decodeFrontmatter(fm []byte, meta *SkillMeta) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(fm, &doc); err != nil {
+		return nil, yamlError(err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	node := doc.Content[0]
+	if node.Kind != yaml.MappingNode {
+		return nil, &FrontmatterError{Line: node.Line, Msg: "failed to parse SKILL.md frontmatter: expected a mapping of fields"}
+	}
+	if err := node.Decode(meta); err != nil {
+		return nil, yamlError(err)
+	}
+	return node, nil
+}
+
+// yamlError wraps a yaml.v3 error in a FrontmatterError carrying its line number.
+func yamlError(err error) error {
+	line := 1
+	var typeErr *yaml.TypeError
+	msg := err.Error()
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ = strconv.Atoi(m[1])
+	}
+	return &FrontmatterError{Line: line, Msg: "failed to parse SKILL.md frontmatter", Err: err}
+}
+
+// extractFrontmatterAndBody separates and parses the frontmatter and body of SKILL.md.
+func extractFrontmatterAndBody(data []byte) (*skillFile, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	fm, next, err := readFrontmatter(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sf := &skillFile{}
+	if sf.node, err = decodeFrontmatter(fm, &sf.meta); err != nil {
+		return nil, err
+	}
+
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.ReplaceAll(string(rest), "\r\n", "\n")
+	trimmedLeft := strings.TrimLeft(body, " \t\r\n")
+	sf.bodyLine = next + strings.Count(body[:len(body)-len(trimmedLeft)], "\n")
+	sf.body = strings.TrimRight(trimmedLeft, " \t\r\n")
+
+	return sf, nil
+}
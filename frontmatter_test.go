@@ -0,0 +1,77 @@
+package goskills
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFrontmatterAndBody_DelimitersInContent(t *testing.T) {
+	content := "---\n" +
+		"name: dashes\n" +
+		"description: Uses --- as an em-dash\n" +
+		"---\n" +
+		"# Title\n" +
+		"\n" +
+		"---\n" +
+		"\n" +
+		"```markdown\n" +
+		"---\n" +
+		"name: nested\n" +
+		"---\n" +
+		"```\n"
+
+	sf, err := extractFrontmatterAndBody([]byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, "dashes", sf.meta.Name)
+	assert.Equal(t, "Uses --- as an em-dash", sf.meta.Description)
+	assert.Equal(t, 5, sf.bodyLine)
+	assert.Equal(t, "# Title\n\n---\n\n```markdown\n---\nname: nested\n---\n```", sf.body)
+}
+
+func TestExtractFrontmatterAndBody_BOMAndCRLF(t *testing.T) {
+	content := "\xef\xbb\xbf---\r\nname: crlf\r\ndescription: Windows line endings\r\n---\r\n\r\n# Body\r\nline two\r\n"
+
+	sf, err := extractFrontmatterAndBody([]byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, "crlf", sf.meta.Name)
+	assert.Equal(t, "Windows line endings", sf.meta.Description)
+	assert.Equal(t, "# Body\nline two", sf.body)
+	assert.Equal(t, 6, sf.bodyLine)
+}
+
+func TestExtractFrontmatterAndBody_DocumentEndMarker(t *testing.T) {
+	sf, err := extractFrontmatterAndBody([]byte("---\nname: dots\ndescription: closed with dots\n...\nBody"))
+	require.NoError(t, err)
+	assert.Equal(t, "dots", sf.meta.Name)
+	assert.Equal(t, "Body", sf.body)
+}
+
+func TestExtractFrontmatterAndBody_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		msg     string
+	}{
+		{"empty", "", 1, "no YAML frontmatter found"},
+		{"not on first line", "# Title\n---\nname: late\n---\n", 1, "no YAML frontmatter found"},
+		{"unterminated", "---\nname: open\ndescription: never closed\n", 1, "unterminated YAML frontmatter"},
+		{"yaml syntax", "---\nname: bad\ndescription: ok\nkey: [\n---\nBody", 4, "failed to parse SKILL.md frontmatter"},
+		{"yaml type", "---\nname: bad\n\nmetadata: [a, b]\n---\nBody", 4, "failed to parse SKILL.md frontmatter"},
+		{"not a mapping", "---\n- a\n- b\n---\nBody", 2, "expected a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extractFrontmatterAndBody([]byte(tt.content))
+			require.Error(t, err)
+			var fmErr *FrontmatterError
+			require.True(t, errors.As(err, &fmErr))
+			assert.Equal(t, tt.line, fmErr.Line)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
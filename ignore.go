@@ -0,0 +1,157 @@
+package goskills
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SkillIgnoreFile is the name of the file at a skill root that lists, in
+// .gitignore syntax, the files to leave out of the skill's inventory.
+const SkillIgnoreFile = ".skillignore"
+
+// defaultExcludes are .gitignore patterns applied before a skill's
+// .skillignore: files that describe the skill rather than belong to it,
+// operating system clutter, editor backups and compiled Python.
+// A .skillignore can re-include any of them with a '!' pattern.
+var defaultExcludes = []string{
+	SkillIgnoreFile,
+	ManifestFileName,
+	SignatureFileName,
+	".DS_Store",
+	"Thumbs.db",
+	"desktop.ini",
+	"*.pyc",
+	"*.pyo",
+	"*.swp",
+	"*~",
+}
+
+// ignoreRule is a single compiled .gitignore pattern.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool // The pattern started with '!' and re-includes matches
+	dirOnly bool // The pattern ended with '/' and only matches directories
+}
+
+// ignoreMatcher decides which paths of a skill are excluded. Later rules take
+// precedence over earlier ones, as in .gitignore.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// addPatterns compiles .gitignore lines and appends them to the matcher. It
+// returns a message for each line that is not a valid pattern, keyed by
+// 1-based line number.
+func (m *ignoreMatcher) addPatterns(lines []string) map[int]string {
+	var problems map[int]string
+	for i, line := range lines {
+		rule, ok, err := compileIgnorePattern(line)
+		if err != nil {
+			if problems == nil {
+				problems = make(map[int]string)
+			}
+			problems[i+1] = err.Error()
+			continue
+		}
+		if ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	return problems
+}
+
+// excluded reports whether the slash-separated path rel, relative to the
+// skill root, is excluded.
+func (m *ignoreMatcher) excluded(rel string, isDir bool) bool {
+	excluded := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.re.MatchString(rel) {
+			excluded = !r.negate
+		}
+	}
+	return excluded
+}
+
+// compileIgnorePattern compiles one line of a .gitignore file. It reports
+// false for blank lines and comments.
+func compileIgnorePattern(line string) (ignoreRule, bool, error) {
+	var rule ignoreRule
+
+	// Trailing spaces are ignored unless escaped with a backslash.
+	if !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line, " \t\r")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false, nil
+	}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false, nil
+	}
+
+	// A pattern with a slash other than a trailing one is relative to the
+	// skill root; otherwise it matches at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "/**") && i+3 == len(line):
+			re.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '\\' && i+1 < len(line):
+			i++
+			re.WriteString(regexp.QuoteMeta(line[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end < 0 {
+				return rule, false, fmt.Errorf("unterminated character class in %q", line)
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return rule, false, fmt.Errorf("invalid pattern %q: %v", line, err)
+	}
+	rule.re = compiled
+	return rule, true, nil
+}
@@ -0,0 +1,89 @@
+package goskills
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := &ignoreMatcher{}
+	problems := m.addPatterns([]string{
+		"# comment",
+		"",
+		"*.log",
+		"!keep.log",
+		"build/",
+		"/top.txt",
+		"docs/**/draft-*.md",
+		"assets/raw/**",
+		`\#literal`,
+		"[abc",
+	})
+	assert.Equal(t, map[int]string{10: `unterminated character class in "[abc"`}, problems)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		excluded bool
+	}{
+		{"debug.log", false, true},
+		{"scripts/debug.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"scripts/build", true, true},
+		{"build", false, false},
+		{"top.txt", false, true},
+		{"nested/top.txt", false, false},
+		{"docs/draft-1.md", false, true},
+		{"docs/a/b/draft-2.md", false, true},
+		{"docs/final.md", false, false},
+		{"assets/raw/big.psd", false, true},
+		{"assets/raw", true, false},
+		{"#literal", false, true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.excluded, m.excluded(tt.path, tt.isDir), tt.path)
+	}
+}
+
+func TestParseSkillPackage_SkillIgnoreAndSizeLimits(t *testing.T) {
+	fsys := fstest.MapFS{
+		"s/SKILL.md":                 {Data: []byte("---\nname: s\ndescription: Ignores.\n---\n")},
+		"s/.skillignore":             {Data: []byte("fixtures/\n*.bak\n!important.bak\n[oops\n")},
+		"s/.DS_Store":                {Data: []byte("junk")},
+		"s/scripts/run.py":           {Data: []byte("print('run')")},
+		"s/scripts/run.pyc":          {Data: []byte("bytecode")},
+		"s/scripts/old.bak":          {Data: []byte("old")},
+		"s/important.bak":            {Data: []byte("keep")},
+		"s/fixtures/data.json":       {Data: []byte("{}")},
+		"s/assets/huge.bin":          {Data: make([]byte, 2048)},
+		"s/references/guide.md":      {Data: []byte("# Guide")},
+		"s/references/guide.md.swp":  {Data: []byte("swap")},
+		"s/references/notes.txt~":    {Data: []byte("backup")},
+		"s/references/more/extra.md": {Data: []byte("# Extra")},
+	}
+
+	pkg, err := ParseSkillPackageFS(fsys, "s", WithMaxFileSize(1024), WithMaxTotalSize(20))
+	require.NoError(t, err)
+
+	var paths []string
+	for _, f := range pkg.Resources.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"important.bak", "references/guide.md", "references/more/extra.md", "scripts/run.py"}, paths)
+
+	require.Len(t, pkg.Warnings, 3)
+	assert.Equal(t, RuleSkillIgnore, pkg.Warnings[0].Rule)
+	assert.Equal(t, "s/.skillignore", pkg.Warnings[0].File)
+	assert.Equal(t, 4, pkg.Warnings[0].Line)
+	assert.Equal(t, RuleFileSize, pkg.Warnings[1].Rule)
+	assert.Equal(t, "s/assets/huge.bin", pkg.Warnings[1].File)
+	assert.Equal(t, RuleSkillSize, pkg.Warnings[2].Rule)
+
+	diags := Validate(pkg)
+	assert.Equal(t, pkg.Warnings, diags[len(diags)-3:])
+	assert.False(t, HasErrors(diags))
+}
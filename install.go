@@ -0,0 +1,430 @@
+package goskills
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxInstallSize bounds the uncompressed size of an archive accepted by
+// InstallSkill, as a guard against decompression bombs.
+const maxInstallSize = 256 << 20
+
+// InstallResult describes a skill installed by InstallSkill.
+type InstallResult struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Path     string `json:"path"`               // Directory the skill was installed to
+	Previous string `json:"previous,omitempty"` // Version that was replaced, if any
+	Replaced bool   `json:"replaced"`           // An installed copy of the skill was replaced
+}
+
+// VersionChange compares the installed version with the one it replaced,
+// returning +1 for an upgrade, -1 for a downgrade and 0 for a reinstall of
+// the same version. It is 0 when no copy was replaced.
+func (r *InstallResult) VersionChange() int {
+	if !r.Replaced {
+		return 0
+	}
+	return compareVersions(r.Version, r.Previous)
+}
+
+// archiveEntry is a regular file read from a skill archive.
+type archiveEntry struct {
+	data       []byte
+	executable bool
+}
+
+// InstallSkill verifies the skill archive at archivePath, as written by
+// PackSkill, and installs it into skillsDir/<name>. The archive must hold a
+// single top-level directory named after the skill, containing a manifest
+// that lists exactly the other files with matching sizes and digests, and
+// optionally a signature, which is installed alongside for VerifySkill. Entries
+// that are not regular files, or whose path is absolute or climbs out of the
+// archive, are refused.
+//
+// An installed copy of the skill is replaced only by a newer version, unless
+// WithForce is given. The new copy is assembled next to the old one and
+// swapped in, so a failed install leaves the old copy in place.
+func InstallSkill(archivePath, skillsDir string, opts ...InstallOption) (*InstallResult, error) {
+	o := newInstallOptions(opts)
+
+	m, entries, err := readSkillArchive(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", archivePath, err)
+	}
+
+	target := filepath.Join(skillsDir, m.Name)
+	result := &InstallResult{Name: m.Name, Version: m.Version, Path: target}
+	if _, err := os.Stat(target); err == nil {
+		result.Replaced = true
+		result.Previous = installedVersion(target)
+		if !o.force {
+			switch c := compareVersions(m.Version, result.Previous); {
+			case c == 0:
+				return nil, fmt.Errorf("skill '%s' version %s is already installed in %s", m.Name, displayVersion(m.Version), skillsDir)
+			case c < 0:
+				return nil, fmt.Errorf("skill '%s' version %s is older than the installed version %s", m.Name, displayVersion(m.Version), displayVersion(result.Previous))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(skillsDir, 0o755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(skillsDir, ".install-"+m.Name+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+	if err := os.Chmod(staging, 0o755); err != nil {
+		return nil, err
+	}
+
+	for rel, e := range entries {
+		name := filepath.Join(staging, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(name, e.data, packedMode(e.executable)); err != nil {
+			return nil, err
+		}
+	}
+	pkg, err := ParseSkillPackage(staging)
+	if err != nil {
+		return nil, fmt.Errorf("archive does not hold a valid skill: %w", err)
+	}
+	if pkg.Meta.Name != m.Name {
+		return nil, fmt.Errorf("SKILL.md names the skill '%s' but the manifest names it '%s'", pkg.Meta.Name, m.Name)
+	}
+
+	if !result.Replaced {
+		if err := os.Rename(staging, target); err != nil {
+			return nil, fmt.Errorf("failed to install skill: %w", err)
+		}
+		return result, nil
+	}
+	backup := staging + ".old"
+	if err := os.Rename(target, backup); err != nil {
+		return nil, fmt.Errorf("failed to move aside the installed skill: %w", err)
+	}
+	if err := os.Rename(staging, target); err != nil {
+		if restoreErr := os.Rename(backup, target); restoreErr != nil {
+			return nil, fmt.Errorf("failed to install skill: %w (the previous copy is kept in %s)", err, backup)
+		}
+		return nil, fmt.Errorf("failed to install skill: %w", err)
+	}
+	if err := os.RemoveAll(backup); err != nil {
+		return nil, fmt.Errorf("skill installed, but the previous copy could not be removed: %w", err)
+	}
+	return result, nil
+}
+
+// ReadManifest reads the manifest of an installed skill from its directory.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return m, nil
+}
+
+// installedVersion returns the version of the skill installed in dir, from
+// its manifest or, for a skill that was not installed from an archive, from
+// its SKILL.md.
+func installedVersion(dir string) string {
+	if m, err := ReadManifest(dir); err == nil {
+		return m.Version
+	}
+	if idx, err := IndexSkillPackage(dir); err == nil {
+		return idx.Meta.Version
+	}
+	return ""
+}
+
+// displayVersion formats a version for messages.
+func displayVersion(v string) string {
+	if v == "" {
+		return "(unversioned)"
+	}
+	return v
+}
+
+// readSkillArchive reads and verifies a zip or gzip-compressed tar skill
+// archive, recognized by its content. It returns the manifest and the files
+// it lists, including the manifest itself, keyed by path relative to the
+// skill root.
+func readSkillArchive(archivePath string) (*Manifest, map[string]archiveEntry, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	magic, err := bufio.NewReader(f).Peek(4)
+	if err != nil {
+		return nil, nil, errors.New("not a skill archive")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	r := &archiveReader{entries: make(map[string]archiveEntry), budget: maxInstallSize}
+	switch {
+	case bytes.Equal(magic, []byte("PK\x03\x04")):
+		info, err := f.Stat()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := r.readZip(f, info.Size()); err != nil {
+			return nil, nil, err
+		}
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		if err := r.readTarGz(f); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, errors.New("not a zip or tar.gz skill archive")
+	}
+
+	m, err := r.verify()
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, r.entries, nil
+}
+
+// archiveReader collects the entries of a skill archive.
+type archiveReader struct {
+	top     string
+	entries map[string]archiveEntry
+	budget  int64 // Bytes that may still be read
+}
+
+func (r *archiveReader) readZip(ra io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return err
+	}
+	for _, zf := range zr.File {
+		mode := zf.Mode()
+		if mode.IsDir() {
+			if _, err := r.entryPath(zf.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !mode.IsRegular() {
+			return fmt.Errorf("archive entry %s is not a regular file", zf.Name)
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = r.add(zf.Name, rc, mode.Perm()&0o111 != 0)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *archiveReader) readTarGz(rd io.Reader) error {
+	gr, err := gzip.NewReader(rd)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if _, err := r.entryPath(hdr.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := r.add(hdr.Name, tr, hdr.Mode&0o111 != 0); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("archive entry %s is not a regular file", hdr.Name)
+		}
+	}
+}
+
+// add reads the regular file entry name from rd.
+func (r *archiveReader) add(name string, rd io.Reader, executable bool) error {
+	rel, err := r.entryPath(name)
+	if err != nil {
+		return err
+	}
+	if rel == "" {
+		return fmt.Errorf("archive entry %s is not inside the skill directory", name)
+	}
+	if _, dup := r.entries[rel]; dup {
+		return fmt.Errorf("archive entry %s appears more than once", name)
+	}
+	data, err := io.ReadAll(io.LimitReader(rd, r.budget+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	r.budget -= int64(len(data))
+	if r.budget < 0 {
+		return fmt.Errorf("archive content exceeds %d bytes", maxInstallSize)
+	}
+	r.entries[rel] = archiveEntry{data: data, executable: executable}
+	return nil
+}
+
+// entryPath checks an archive entry name and returns it relative to the
+// top-level directory, which must be the same for every entry.
+func (r *archiveReader) entryPath(name string) (string, error) {
+	trimmed := strings.TrimSuffix(name, "/")
+	if trimmed == "" || strings.HasPrefix(trimmed, "/") || strings.Contains(trimmed, `\`) || filepath.VolumeName(trimmed) != "" {
+		return "", fmt.Errorf("archive entry %s has an unsafe path", name)
+	}
+	parts := strings.Split(trimmed, "/")
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("archive entry %s has an unsafe path", name)
+		}
+	}
+	if r.top == "" {
+		r.top = parts[0]
+	} else if parts[0] != r.top {
+		return "", fmt.Errorf("archive holds more than one top-level directory: %s and %s", r.top, parts[0])
+	}
+	return strings.Join(parts[1:], "/"), nil
+}
+
+// verify checks the collected entries against the archive's manifest.
+func (r *archiveReader) verify() (*Manifest, error) {
+	raw, ok := r.entries[ManifestFileName]
+	if !ok {
+		return nil, fmt.Errorf("archive has no %s", ManifestFileName)
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(raw.data, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if err := ValidateSkillName(m.Name); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.Name != r.top {
+		return nil, fmt.Errorf("manifest names the skill '%s' but the archive directory is '%s'", m.Name, r.top)
+	}
+
+	listed := make(map[string]bool, len(m.Files))
+	for _, mf := range m.Files {
+		listed[mf.Path] = true
+		e, ok := r.entries[mf.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s is listed in the manifest but missing from the archive", mf.Path)
+		}
+		sum := sha256.Sum256(e.data)
+		if int64(len(e.data)) != mf.Size || hex.EncodeToString(sum[:]) != mf.SHA256 {
+			return nil, fmt.Errorf("%s does not match its manifest entry", mf.Path)
+		}
+	}
+	if !listed["SKILL.md"] {
+		return nil, errors.New("manifest does not list SKILL.md")
+	}
+	for rel := range r.entries {
+		if rel != ManifestFileName && rel != SignatureFileName && !listed[rel] {
+			return nil, fmt.Errorf("%s is not listed in the manifest", rel)
+		}
+	}
+	return m, nil
+}
+
+// compareVersions compares two version strings, such as "1.2.0" or
+// "v2.0.0-beta.1", returning -1, 0 or +1. Semantic versions compare by
+// semver precedence. Other dotted versions compare leniently: numeric parts
+// compare as numbers, missing parts count as zero, and a pre-release sorts
+// before its release. An empty version sorts before any other.
+func compareVersions(a, b string) int {
+	if va, err := ParseVersion(a); err == nil {
+		if vb, err := ParseVersion(b); err == nil {
+			return va.Compare(vb)
+		}
+	}
+	if a == "" || b == "" {
+		switch {
+		case a == b:
+			return 0
+		case a == "":
+			return -1
+		default:
+			return 1
+		}
+	}
+	a, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	b, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	if c := compareDotted(a, b); c != 0 {
+		return c
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareDotted(aPre, bPre)
+}
+
+// compareDotted compares dot-separated identifiers part by part.
+func compareDotted(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.ParseUint(x, 10, 64)
+		yn, yerr := strconv.ParseUint(y, 10, 64)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,378 @@
+package goskills
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Document is the structure of a SKILL.md body: its heading tree, fenced code
+// blocks and lists. Line numbers refer to SKILL.md when the document was built
+// by ParseSkillPackage, and to the body itself when built by ParseMarkdown.
+type Document struct {
+	Sections   []*Section  `json:"sections,omitempty"`    // Top-level sections; deeper headings are nested as children
+	CodeBlocks []CodeBlock `json:"code_blocks,omitempty"` // Fenced code blocks in order of appearance
+	Lists      []List      `json:"lists,omitempty"`       // Bulleted and numbered lists in order of appearance
+
+	lines     []string // Lines of the body
+	firstLine int      // Line number of lines[0]
+}
+
+// Section is a heading and the content up to the next heading of the same or a higher level.
+type Section struct {
+	Level    int        `json:"level"`              // Heading level, 1 to 6
+	Title    string     `json:"title"`              // Heading text
+	Anchor   string     `json:"anchor"`             // GitHub-style anchor, unique within the document
+	Line     int        `json:"line"`               // Line of the heading
+	EndLine  int        `json:"end_line"`           // Last line of the section, including its subsections
+	Children []*Section `json:"children,omitempty"` // Subsections
+
+	contentEnd int // Last line before the next heading of any level
+}
+
+// CodeBlock is a fenced code block.
+type CodeBlock struct {
+	Language string `json:"language,omitempty"` // First word of the info string, such as "python"
+	Info     string `json:"info,omitempty"`     // Full info string after the opening fence
+	Code     string `json:"code"`               // Content between the fences
+	Line     int    `json:"line"`               // Line of the opening fence
+	EndLine  int    `json:"end_line"`           // Line of the closing fence, or the last line if unterminated
+	Section  string `json:"section,omitempty"`  // Anchor of the enclosing section
+}
+
+// List is a run of consecutive list items.
+type List struct {
+	Ordered bool       `json:"ordered"`
+	Items   []ListItem `json:"items"`
+	Line    int        `json:"line"`              // Line of the first item
+	EndLine int        `json:"end_line"`          // Last line of the list
+	Section string     `json:"section,omitempty"` // Anchor of the enclosing section
+}
+
+// ListItem is a single list entry. Nested items follow their parent with a greater Depth.
+type ListItem struct {
+	Text  string `json:"text"`
+	Depth int    `json:"depth"` // 0 for top-level items
+	Line  int    `json:"line"`
+}
+
+// listItemPattern matches a bullet or numbered list item; group 1 is the
+// indentation, group 2 the marker and group 3 the item text.
+var listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+
+// ParseMarkdown builds the Document of a markdown body. Line numbers start at 1
+// on the first line of body.
+func ParseMarkdown(body string) *Document {
+	return parseMarkdown(body, 1)
+}
+
+// parseMarkdown builds the Document of body, numbering its first line firstLine.
+func parseMarkdown(body string, firstLine int) *Document {
+	doc := &Document{lines: strings.Split(body, "\n"), firstLine: firstLine}
+	lastLine := firstLine + len(doc.lines) - 1
+
+	var (
+		stack   []*Section // Open sections, outermost first
+		all     []*Section // Every section in document order
+		anchors = make(map[string]int)
+		fence   string
+		block   *CodeBlock
+		code    []string
+		list    *List
+		indents []int // Indentation of the open list levels
+		anchor  string
+	)
+
+	closeList := func() {
+		if list != nil {
+			doc.Lists = append(doc.Lists, *list)
+			list, indents = nil, nil
+		}
+	}
+
+	for i, line := range doc.lines {
+		lineNo := firstLine + i
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			marker := m[1]
+			switch {
+			case fence == "":
+				closeList()
+				fence = marker
+				info := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), marker[:1]))
+				block = &CodeBlock{Info: info, Line: lineNo, Section: anchor}
+				if fields := strings.Fields(info); len(fields) > 0 {
+					block.Language = fields[0]
+				}
+				code = nil
+				continue
+			case marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line) == strings.TrimSpace(marker):
+				block.Code = strings.Join(code, "\n")
+				block.EndLine = lineNo
+				doc.CodeBlocks = append(doc.CodeBlocks, *block)
+				fence, block = "", nil
+				continue
+			}
+		}
+		if fence != "" {
+			code = append(code, line)
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			closeList()
+			s := &Section{Level: len(m[1]), Title: m[2], Line: lineNo}
+			s.Anchor = uniqueAnchor(headingAnchor(s.Title), anchors)
+			if len(all) > 0 {
+				all[len(all)-1].contentEnd = lineNo - 1
+			}
+			for len(stack) > 0 && stack[len(stack)-1].Level >= s.Level {
+				stack[len(stack)-1].EndLine = lineNo - 1
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				doc.Sections = append(doc.Sections, s)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, s)
+			}
+			stack = append(stack, s)
+			all = append(all, s)
+			anchor = s.Anchor
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if m := listItemPattern.FindStringSubmatch(line); m != nil {
+			indent := len(m[1])
+			if list == nil {
+				list = &List{Line: lineNo, Section: anchor}
+				list.Ordered = !strings.ContainsAny(m[2], "-*+")
+				indents = []int{indent}
+			}
+			for len(indents) > 1 && indent < indents[len(indents)-1] {
+				indents = indents[:len(indents)-1]
+			}
+			if indent > indents[len(indents)-1] {
+				indents = append(indents, indent)
+			}
+			list.Items = append(list.Items, ListItem{Text: strings.TrimSpace(m[3]), Depth: len(indents) - 1, Line: lineNo})
+			list.EndLine = lineNo
+			continue
+		}
+		if list != nil && (line[0] == ' ' || line[0] == '\t') {
+			// Continuation of the last item.
+			list.EndLine = lineNo
+			continue
+		}
+		closeList()
+	}
+
+	if block != nil {
+		block.Code = strings.Join(code, "\n")
+		block.EndLine = lastLine
+		doc.CodeBlocks = append(doc.CodeBlocks, *block)
+	}
+	closeList()
+	for _, s := range stack {
+		s.EndLine = lastLine
+	}
+	if len(all) > 0 {
+		all[len(all)-1].contentEnd = lastLine
+	}
+	return doc
+}
+
+// headingAnchor derives the anchor GitHub generates for a heading: lowercase
+// letters, digits, hyphens and underscores, with spaces turned into hyphens.
+func headingAnchor(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// uniqueAnchor suffixes repeated anchors with -1, -2, ... as GitHub does.
+func uniqueAnchor(anchor string, seen map[string]int) string {
+	n, dup := seen[anchor]
+	seen[anchor] = n + 1
+	if !dup {
+		return anchor
+	}
+	return anchor + "-" + strconv.Itoa(n)
+}
+
+// Headings returns every section of the document, in order of appearance.
+func (d *Document) Headings() []*Section {
+	var all []*Section
+	var walk func([]*Section)
+	walk = func(sections []*Section) {
+		for _, s := range sections {
+			all = append(all, s)
+			walk(s.Children)
+		}
+	}
+	walk(d.Sections)
+	return all
+}
+
+// Section returns the section with the given anchor, or nil.
+func (d *Document) Section(anchor string) *Section {
+	for _, s := range d.Headings() {
+		if s.Anchor == anchor {
+			return s
+		}
+	}
+	return nil
+}
+
+// Text returns the markdown of a section, including its heading and subsections.
+func (d *Document) Text(s *Section) string {
+	return d.span(s.Line, s.EndLine)
+}
+
+// CodeBlocksByLanguage returns the code blocks whose language matches lang, ignoring case.
+func (d *Document) CodeBlocksByLanguage(lang string) []CodeBlock {
+	var blocks []CodeBlock
+	for _, b := range d.CodeBlocks {
+		if strings.EqualFold(b.Language, lang) {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// span joins the lines from start to end, both inclusive.
+func (d *Document) span(start, end int) string {
+	from, to := start-d.firstLine, end-d.firstLine+1
+	if from < 0 {
+		from = 0
+	}
+	if to > len(d.lines) {
+		to = len(d.lines)
+	}
+	if from >= to {
+		return ""
+	}
+	return strings.Join(d.lines[from:to], "\n")
+}
+
+// omittedMarker replaces the content of sections left out by Excerpt.
+const omittedMarker = "(section omitted for length)"
+
+// Excerpt returns the body shortened to at most maxChars characters, keeping
+// the sections most relevant to query. Text before the first heading and every
+// heading are always kept so the outline stays intact; the content of the
+// remaining sections is added by relevance, ties going to earlier sections,
+// and omitted sections are marked. The body is returned unchanged when it fits.
+func (d *Document) Excerpt(query string, maxChars int) string {
+	body := strings.Join(d.lines, "\n")
+	if maxChars <= 0 || utf8.RuneCountInString(body) <= maxChars {
+		return body
+	}
+
+	sections := d.Headings()
+	preambleEnd := d.firstLine + len(d.lines) - 1
+	if len(sections) > 0 {
+		preambleEnd = sections[0].Line - 1
+	}
+	preamble := strings.TrimRight(d.span(d.firstLine, preambleEnd), "\n")
+
+	// The outline is mandatory: the preamble, each heading and a marker per
+	// section, with the line breaks that separate them.
+	budget := maxChars - utf8.RuneCountInString(preamble)
+	contents := make([]string, len(sections))
+	for i, s := range sections {
+		budget -= utf8.RuneCountInString(d.lines[s.Line-d.firstLine]) + len(omittedMarker) + 4
+		contents[i] = strings.Trim(d.span(s.Line+1, s.contentEnd), "\n")
+	}
+	if budget < 0 {
+		return truncateRunes(body, maxChars)
+	}
+
+	terms := queryTerms(query)
+	order := make([]int, len(sections))
+	scores := make([]int, len(sections))
+	for i, s := range sections {
+		order[i] = i
+		scores[i] = 3*countTerms(s.Title, terms) + countTerms(contents[i], terms)
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	keep := make([]bool, len(sections))
+	for _, i := range order {
+		// Kept content replaces the marker, whose cost was already paid.
+		cost := utf8.RuneCountInString(contents[i]) - len(omittedMarker)
+		if contents[i] == "" || cost <= budget {
+			keep[i] = true
+			budget -= cost
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(preamble)
+	for i, s := range sections {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(d.lines[s.Line-d.firstLine])
+		switch {
+		case keep[i] && contents[i] == "":
+		case keep[i]:
+			b.WriteString("\n\n" + contents[i] + "\n")
+		default:
+			b.WriteString("\n\n" + omittedMarker + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// queryTerms splits a query into distinct lowercase words of three or more characters.
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(w) >= 3 && !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// countTerms reports how many of terms occur in text, ignoring case.
+func countTerms(text string, terms []string) int {
+	text = strings.ToLower(text)
+	n := 0
+	for _, t := range terms {
+		if strings.Contains(text, t) {
+			n++
+		}
+	}
+	return n
+}
+
+// truncateRunes cuts s to at most n characters, preferring to end at a line break.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	cut := []rune(s)[:n]
+	if i := strings.LastIndexByte(string(cut), '\n'); i > 0 {
+		return string(cut)[:i]
+	}
+	return string(cut)
+}
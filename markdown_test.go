@@ -0,0 +1,107 @@
+package goskills
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const markdownBody = `Intro text.
+
+# Guide
+
+## Setup
+
+1. Install it
+2. Configure it
+   - set the key
+   - set the URL
+
+` + "```bash\npip install tool\n```" + `
+
+## Usage
+
+- Run it
+* Check it
+
+` + "```python extra\nprint('hi')\n```" + `
+
+## Setup
+
+` + "```\nunterminated"
+
+func TestParseMarkdown(t *testing.T) {
+	doc := ParseMarkdown(markdownBody)
+
+	require.Len(t, doc.Sections, 1)
+	guide := doc.Sections[0]
+	assert.Equal(t, "Guide", guide.Title)
+	assert.Equal(t, "guide", guide.Anchor)
+	assert.Equal(t, 3, guide.Line)
+	assert.Equal(t, 28, guide.EndLine)
+
+	require.Len(t, guide.Children, 3)
+	assert.Equal(t, "setup", guide.Children[0].Anchor)
+	assert.Equal(t, 5, guide.Children[0].Line)
+	assert.Equal(t, 15, guide.Children[0].EndLine)
+	assert.Equal(t, "usage", guide.Children[1].Anchor)
+	assert.Equal(t, "setup-1", guide.Children[2].Anchor)
+	assert.Equal(t, guide.Children[1], doc.Section("usage"))
+	assert.Len(t, doc.Headings(), 4)
+
+	require.Len(t, doc.CodeBlocks, 3)
+	assert.Equal(t, CodeBlock{Language: "bash", Info: "bash", Code: "pip install tool", Line: 12, EndLine: 14, Section: "setup"}, doc.CodeBlocks[0])
+	assert.Equal(t, "python extra", doc.CodeBlocks[1].Info)
+	assert.Equal(t, "unterminated", doc.CodeBlocks[2].Code)
+	assert.Equal(t, 28, doc.CodeBlocks[2].EndLine)
+	assert.Len(t, doc.CodeBlocksByLanguage("PYTHON"), 1)
+
+	require.Len(t, doc.Lists, 2)
+	assert.True(t, doc.Lists[0].Ordered)
+	assert.Equal(t, 7, doc.Lists[0].Line)
+	assert.Equal(t, 10, doc.Lists[0].EndLine)
+	assert.Equal(t, []ListItem{
+		{Text: "Install it", Depth: 0, Line: 7},
+		{Text: "Configure it", Depth: 0, Line: 8},
+		{Text: "set the key", Depth: 1, Line: 9},
+		{Text: "set the URL", Depth: 1, Line: 10},
+	}, doc.Lists[0].Items)
+	assert.False(t, doc.Lists[1].Ordered)
+	assert.Equal(t, "usage", doc.Lists[1].Section)
+	assert.Len(t, doc.Lists[1].Items, 2)
+
+	assert.True(t, strings.HasPrefix(doc.Text(doc.Section("usage")), "## Usage\n\n- Run it"))
+}
+
+func TestParseSkillPackage_Document(t *testing.T) {
+	pkg, err := ParseSkillPackage("./examples/skills/document-skills/pdf")
+	require.NoError(t, err)
+	require.NotNil(t, pkg.Document)
+
+	quickStart := pkg.Document.Section("quick-start")
+	require.NotNil(t, quickStart)
+	assert.Equal(t, 13, quickStart.Line, "line numbers refer to SKILL.md")
+	assert.NotEmpty(t, pkg.Document.CodeBlocksByLanguage("python"))
+}
+
+func TestDocument_Excerpt(t *testing.T) {
+	body := "Preamble.\n\n# Skill\n\n## Merge\n\n" + strings.Repeat("merge details ", 40) +
+		"\n\n## Watermark\n\n" + strings.Repeat("watermark details ", 40) +
+		"\n\n## Encrypt\n\n" + strings.Repeat("encrypt details ", 40)
+	doc := ParseMarkdown(body)
+
+	assert.Equal(t, body, doc.Excerpt("anything", 0))
+	assert.Equal(t, body, doc.Excerpt("anything", len(body)))
+
+	excerpt := doc.Excerpt("add a watermark", 1000)
+	assert.LessOrEqual(t, utf8.RuneCountInString(excerpt), 1000)
+	assert.True(t, strings.HasPrefix(excerpt, "Preamble.\n# Skill\n## Merge"))
+	assert.Contains(t, excerpt, "watermark details")
+	assert.Contains(t, excerpt, "## Encrypt\n\n"+omittedMarker)
+	assert.NotContains(t, excerpt, "encrypt details")
+
+	assert.LessOrEqual(t, utf8.RuneCountInString(doc.Excerpt("", 20)), 20)
+}
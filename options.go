@@ -0,0 +1,337 @@
+package goskills
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"time"
+
+	"github.com/smallnest/goskills/tool"
+)
+
+// ParseOption configures how skill packages are discovered and parsed.
+type ParseOption func(*parseOptions)
+
+// parseOptions holds the settings applied by ParseOption values.
+type parseOptions struct {
+	strict       bool          // Abort on the first package that fails to parse
+	sortByName   bool          // Order packages by skill name instead of by path
+	concurrency  int           // Number of packages parsed at the same time
+	ignore       []string      // Glob patterns of directory names that are not descended into
+	maxFileSize  int64         // Files larger than this are left out of the inventory; 0 means no limit
+	maxTotalSize int64         // A skill whose files add up to more than this is warned about; 0 means no limit
+	symlinks     SymlinkPolicy // Handling of symbolic links that resolve outside the skill root
+}
+
+// SymlinkPolicy decides what happens to a symbolic link in a skill that
+// resolves outside the skill directory.
+type SymlinkPolicy string
+
+const (
+	// SymlinkAllow follows such links as if their targets were part of the
+	// skill: they are read and scripts among them become tools.
+	SymlinkAllow SymlinkPolicy = "allow"
+	// SymlinkMark lists such links with ResourceFile.EscapesRoot set and a
+	// warning, without reading them or exposing them as tools. It is the default.
+	SymlinkMark SymlinkPolicy = "mark"
+	// SymlinkSkip leaves such links out of the inventory with a warning.
+	SymlinkSkip SymlinkPolicy = "skip"
+	// SymlinkReject fails parsing of a skill that contains such a link.
+	SymlinkReject SymlinkPolicy = "reject"
+)
+
+// ParseSymlinkPolicy returns the policy with the given name.
+func ParseSymlinkPolicy(name string) (SymlinkPolicy, error) {
+	switch p := SymlinkPolicy(name); p {
+	case SymlinkAllow, SymlinkMark, SymlinkSkip, SymlinkReject:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown symlink policy '%s' (expected allow, mark, skip or reject)", name)
+}
+
+// defaultIgnores are directory names skipped while discovering skills and
+// their files: version control metadata, dependency trees and caches.
+var defaultIgnores = []string{".git", "node_modules", ".venv", "__pycache__"}
+
+// newParseOptions applies opts over the default settings.
+func newParseOptions(opts []ParseOption) *parseOptions {
+	o := &parseOptions{
+		concurrency: runtime.GOMAXPROCS(0),
+		ignore:      append([]string(nil), defaultIgnores...),
+		symlinks:    SymlinkMark,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	return o
+}
+
+// ignored reports whether the directory with the given base name is skipped.
+func (o *parseOptions) ignored(name string) bool {
+	for _, pattern := range o.ignore {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithStrict makes multi-package parsing abort with an error on the first
+// skill package that fails to parse, instead of recording the failure and
+// continuing.
+func WithStrict() ParseOption {
+	return func(o *parseOptions) {
+		o.strict = true
+	}
+}
+
+// WithSortByName orders the packages returned by multi-package parsing by
+// skill name (ties broken by path) instead of by directory path.
+func WithSortByName() ParseOption {
+	return func(o *parseOptions) {
+		o.sortByName = true
+	}
+}
+
+// WithConcurrency sets how many skill packages multi-package parsing reads
+// at the same time. The default is GOMAXPROCS; 1 parses them one by one.
+func WithConcurrency(n int) ParseOption {
+	return func(o *parseOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithIgnore skips directories whose name matches one of the glob patterns
+// (as in path.Match), both when looking for skills and when listing their
+// files. The patterns add to the defaults: .git, node_modules, .venv and
+// __pycache__.
+func WithIgnore(patterns ...string) ParseOption {
+	return func(o *parseOptions) {
+		o.ignore = append(o.ignore, patterns...)
+	}
+}
+
+// WithMaxFileSize leaves files larger than n bytes out of a skill's resource
+// inventory, recording a warning in SkillPackage.Warnings for each of them.
+func WithMaxFileSize(n int64) ParseOption {
+	return func(o *parseOptions) {
+		o.maxFileSize = n
+	}
+}
+
+// WithMaxTotalSize records a warning in SkillPackage.Warnings when the files
+// in a skill's resource inventory add up to more than n bytes.
+func WithMaxTotalSize(n int64) ParseOption {
+	return func(o *parseOptions) {
+		o.maxTotalSize = n
+	}
+}
+
+// WithSymlinkPolicy sets how symbolic links that resolve outside the skill
+// directory are handled, both for SKILL.md and for the resource inventory. The
+// default is SymlinkMark.
+func WithSymlinkPolicy(p SymlinkPolicy) ParseOption {
+	return func(o *parseOptions) {
+		o.symlinks = p
+	}
+}
+
+// InstallOption configures InstallSkill.
+type InstallOption func(*installOptions)
+
+// installOptions holds the settings applied by InstallOption values.
+type installOptions struct {
+	force bool // Replace an installed copy whatever its version
+}
+
+// newInstallOptions applies opts over the default settings.
+func newInstallOptions(opts []InstallOption) *installOptions {
+	o := &installOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithForce makes InstallSkill replace an installed copy of the skill even
+// when it has the same or a newer version.
+func WithForce() InstallOption {
+	return func(o *installOptions) {
+		o.force = true
+	}
+}
+
+// ScaffoldOption configures CreateSkill.
+type ScaffoldOption func(*scaffoldOptions)
+
+// scaffoldOptions holds the settings applied by ScaffoldOption values.
+type scaffoldOptions struct {
+	description  string   // Frontmatter description; a placeholder when empty
+	allowedTools []string // Frontmatter allowed-tools
+	resources    []string // Resource directories to create with an example file
+	license      string   // License identifier recorded in the frontmatter and LICENSE.txt
+	template     string   // Skill directory whose files and body the new skill starts from
+}
+
+// newScaffoldOptions applies opts over the default settings.
+func newScaffoldOptions(opts []ScaffoldOption) *scaffoldOptions {
+	o := &scaffoldOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDescription sets the description of a new skill.
+func WithDescription(description string) ScaffoldOption {
+	return func(o *scaffoldOptions) {
+		o.description = description
+	}
+}
+
+// WithAllowedTools sets the allowed-tools of a new skill.
+func WithAllowedTools(tools ...string) ScaffoldOption {
+	return func(o *scaffoldOptions) {
+		o.allowedTools = append(o.allowedTools, tools...)
+	}
+}
+
+// WithResourceDirs creates the given resource directories in a new skill,
+// each with an example file: "scripts", "references", "assets" or
+// "templates".
+func WithResourceDirs(dirs ...string) ScaffoldOption {
+	return func(o *scaffoldOptions) {
+		o.resources = append(o.resources, dirs...)
+	}
+}
+
+// WithLicense records a license, such as an SPDX identifier, in a new skill's
+// frontmatter and adds a LICENSE.txt for its terms.
+func WithLicense(license string) ScaffoldOption {
+	return func(o *scaffoldOptions) {
+		o.license = license
+	}
+}
+
+// WithTemplate starts a new skill from the skill in dir: its files and
+// SKILL.md body are copied and its frontmatter is kept, apart from the name
+// and the fields set by other options.
+func WithTemplate(dir string) ScaffoldOption {
+	return func(o *scaffoldOptions) {
+		o.template = dir
+	}
+}
+
+// ToolOption configures how tools are generated for a skill's scripts.
+type ToolOption func(*toolOptions)
+
+// toolOptions holds the settings applied by ToolOption values.
+type toolOptions struct {
+	helpTimeout  time.Duration      // How long a script may take to print its --help; 0 disables help capture
+	interpreters *tool.Interpreters // Decides which scripts become tools and what runs them
+	base         *tool.Registry     // Tools offered next to the scripts; nil for tool.BaseTools
+}
+
+// newToolOptions applies opts over the default settings.
+func newToolOptions(opts []ToolOption) *toolOptions {
+	o := &toolOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.interpreters == nil {
+		o.interpreters = tool.DefaultInterpreters()
+	}
+	return o
+}
+
+// baseTools returns the tools offered next to the scripts of skill.
+func (o *toolOptions) baseTools(skill SkillPackage) []tool.Tool {
+	if o.base == nil {
+		return tool.BaseTools(skill.Path)
+	}
+	return o.base.Tools()
+}
+
+// WithBaseTools sets the tools offered next to a skill's scripts, in place
+// of tool.BaseTools, so that applications can add their own tools or leave
+// out the built-in ones. allowed-tools and disallowed-tools still apply.
+func WithBaseTools(registry *tool.Registry) ToolOption {
+	return func(o *toolOptions) {
+		o.base = registry
+	}
+}
+
+// WithInterpreters sets the interpreters that run script tools, by extension
+// and shebang. Files under scripts/ that none of them runs, such as data files
+// and documentation, do not become tools. The default is
+// tool.DefaultInterpreters.
+func WithInterpreters(interpreters *tool.Interpreters) ToolOption {
+	return func(o *toolOptions) {
+		o.interpreters = interpreters
+	}
+}
+
+// WithHelpCapture lets Python scripts that use argparse, and declare their
+// interface neither in a sidecar spec nor in a header, describe it by
+// running them with --help, using the interpreter that runs them as tools.
+// Each run is stopped after timeout. Since this
+// executes the scripts, it is off by default.
+func WithHelpCapture(timeout time.Duration) ToolOption {
+	return func(o *toolOptions) {
+		o.helpTimeout = timeout
+	}
+}
+
+// WatchOption configures Registry.Watch.
+type WatchOption func(*watchOptions)
+
+// watchOptions holds the settings applied by WatchOption values.
+type watchOptions struct {
+	debounce     time.Duration // Quiet period after a change notification before re-indexing
+	pollInterval time.Duration // Interval between scans when polling
+	polling      bool          // Poll even where change notifications are available
+}
+
+// defaultPollInterval is how often Watch scans the roots when it polls.
+const defaultPollInterval = 2 * time.Second
+
+// newWatchOptions applies opts over the default settings.
+func newWatchOptions(opts []WatchOption) *watchOptions {
+	o := &watchOptions{debounce: 200 * time.Millisecond, pollInterval: defaultPollInterval}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.pollInterval <= 0 {
+		o.pollInterval = defaultPollInterval
+	}
+	return o
+}
+
+// WithDebounce sets how long Watch waits for changes to settle before
+// re-indexing, so that an editor saving several files triggers one update.
+// The default is 200ms.
+func WithDebounce(d time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.debounce = d
+	}
+}
+
+// WithPollInterval sets how often Watch scans the roots when it polls. The
+// default is 2s, which a non-positive interval keeps.
+func WithPollInterval(d time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.pollInterval = d
+	}
+}
+
+// WithPolling makes Watch poll the roots even on platforms with change
+// notifications, for file systems that do not deliver them, such as some
+// network mounts.
+func WithPolling() WatchOption {
+	return func(o *watchOptions) {
+		o.polling = true
+	}
+}
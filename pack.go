@@ -0,0 +1,221 @@
+package goskills
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ManifestFileName is the name of the manifest stored at the root of a packed
+// or installed skill. It is never part of the skill's resource inventory.
+const ManifestFileName = ".skill-manifest.json"
+
+// ManifestSpecVersion is the version of the manifest format written by PackSkill.
+const ManifestSpecVersion = "1"
+
+// Manifest describes the contents of a packed skill: its metadata and the
+// size and digest of every file, so that an archive can be verified before
+// it is installed.
+type Manifest struct {
+	SpecVersion string            `json:"spec_version"`
+	Name        string            `json:"name"`
+	Version     string            `json:"version,omitempty"`
+	Description string            `json:"description"`
+	Author      string            `json:"author,omitempty"`
+	License     string            `json:"license,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Files       []ManifestFile    `json:"files"` // Ordered by path, including SKILL.md
+}
+
+// ManifestFile is a file listed in a Manifest.
+type ManifestFile struct {
+	Path       string `json:"path"` // Slash-separated path relative to the skill root
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	Executable bool   `json:"executable,omitempty"`
+}
+
+// ArchiveFormat is a file format for packed skills.
+type ArchiveFormat string
+
+const (
+	ArchiveZip   ArchiveFormat = "zip"    // A zip file, conventionally named <name>.skill
+	ArchiveTarGz ArchiveFormat = "tar.gz" // A gzip-compressed tar file
+)
+
+// packEpoch is the modification time recorded for every archive entry, so
+// that packing the same files twice produces identical archives.
+var packEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// packedFile is a file to be written to an archive.
+type packedFile struct {
+	ManifestFile
+	data []byte
+}
+
+// PackSkill writes pkg to w as an archive in the given format and returns
+// the manifest stored in it. Every entry sits under a top-level directory
+// named after the skill, starting with the manifest; entries are ordered by
+// path and carry fixed timestamps and permissions, so the output depends only
+// on the skill's files. A signature made with SignSkill is included while
+// it still matches the skill. Files that changed since pkg was parsed, or that
+// are symbolic links leaving the skill, make packing fail.
+func PackSkill(pkg *SkillPackage, w io.Writer, format ArchiveFormat) (*Manifest, error) {
+	m, files, err := buildManifest(pkg)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := marshalManifest(m)
+	if err != nil {
+		return nil, err
+	}
+	files = append([]packedFile{{ManifestFile: ManifestFile{Path: ManifestFileName}, data: manifest}}, files...)
+
+	// A signature made for exactly this manifest travels with it.
+	if stored, err := pkg.ReadResource(ManifestFileName); err == nil && bytes.Equal(stored, manifest) {
+		if sig, err := pkg.ReadResource(SignatureFileName); err == nil {
+			files = append(files, packedFile{ManifestFile: ManifestFile{Path: SignatureFileName}, data: sig})
+		}
+	}
+
+	switch format {
+	case ArchiveZip:
+		err = writeZip(w, m.Name, files)
+	case ArchiveTarGz:
+		err = writeTarGz(w, m.Name, files)
+	default:
+		err = fmt.Errorf("unknown archive format '%s' (expected zip or tar.gz)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BuildManifest reads every file of pkg and describes them in a manifest,
+// as PackSkill stores it. Files that changed since pkg was parsed, or that
+// are symbolic links leaving the skill, make it fail.
+func BuildManifest(pkg *SkillPackage) (*Manifest, error) {
+	m, _, err := buildManifest(pkg)
+	return m, err
+}
+
+// buildManifest returns the manifest of pkg together with the content of
+// the files it lists.
+func buildManifest(pkg *SkillPackage) (*Manifest, []packedFile, error) {
+	if err := ValidateSkillName(pkg.Meta.Name); err != nil {
+		return nil, nil, err
+	}
+
+	skillMd, err := pkg.ReadResource("SKILL.md")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read SKILL.md: %w", err)
+	}
+	files := []packedFile{newPackedFile("SKILL.md", skillMd, false)}
+	for _, f := range pkg.Resources.Files {
+		rel := filepath.ToSlash(f.Path)
+		if !pkg.contentAllowed(f) {
+			return nil, nil, fmt.Errorf("%s: %w", rel, errLinkEscapes)
+		}
+		data, err := pkg.ReadResource(f.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		pf := newPackedFile(rel, data, f.Executable)
+		if f.SHA256 != "" && f.SHA256 != pf.SHA256 {
+			return nil, nil, fmt.Errorf("%s changed since the skill was parsed", rel)
+		}
+		files = append(files, pf)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	m := &Manifest{
+		SpecVersion: ManifestSpecVersion,
+		Name:        pkg.Meta.Name,
+		Version:     pkg.Meta.Version,
+		Description: pkg.Meta.Description,
+		Author:      pkg.Meta.Author,
+		License:     pkg.Meta.License,
+		Metadata:    pkg.Meta.Metadata,
+	}
+	for _, f := range files {
+		m.Files = append(m.Files, f.ManifestFile)
+	}
+	return m, files, nil
+}
+
+// newPackedFile describes a file of the skill with the given content.
+func newPackedFile(rel string, data []byte, executable bool) packedFile {
+	sum := sha256.Sum256(data)
+	return packedFile{
+		ManifestFile: ManifestFile{Path: rel, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:]), Executable: executable},
+		data:         data,
+	}
+}
+
+// marshalManifest encodes m as indented JSON.
+func marshalManifest(m *Manifest) ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// packedMode returns the permissions recorded for a packed file.
+func packedMode(executable bool) fs.FileMode {
+	if executable {
+		return 0o755
+	}
+	return 0o644
+}
+
+func writeZip(w io.Writer, top string, files []packedFile) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		hdr := &zip.FileHeader{Name: top + "/" + f.Path, Method: zip.Deflate, Modified: packEpoch}
+		hdr.SetMode(packedMode(f.Executable))
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+	}
+	return zw.Close()
+}
+
+func writeTarGz(w io.Writer, top string, files []packedFile) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     top + "/" + f.Path,
+			Mode:     int64(packedMode(f.Executable)),
+			Size:     int64(len(f.data)),
+			ModTime:  packEpoch,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,187 @@
+package goskills
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packTestSkill writes a skill with a script and a reference to dir/<name>
+// and returns its package.
+func packTestSkill(t *testing.T, dir, name, version string) *SkillPackage {
+	t.Helper()
+	skillDir := filepath.Join(dir, name)
+	writeSkill(t, skillDir, "name: "+name+"\ndescription: Packing test.\nversion: "+version)
+	require.NoError(t, os.MkdirAll(filepath.Join(skillDir, "scripts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "scripts", "run.sh"), []byte("echo "+version), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "guide.md"), []byte("# Guide"), 0o644))
+	pkg, err := ParseSkillPackage(skillDir)
+	require.NoError(t, err)
+	return pkg
+}
+
+// packTo packs pkg into a file in dir and returns its path.
+func packTo(t *testing.T, pkg *SkillPackage, dir string, format ArchiveFormat) string {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := PackSkill(pkg, &buf, format)
+	require.NoError(t, err)
+	archive := filepath.Join(dir, pkg.Meta.Name+"-"+pkg.Meta.Version+"."+string(format))
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o644))
+	return archive
+}
+
+func TestPackSkill(t *testing.T) {
+	pkg := packTestSkill(t, t.TempDir(), "packer", "1.0.0")
+
+	var first, second bytes.Buffer
+	m, err := PackSkill(pkg, &first, ArchiveZip)
+	require.NoError(t, err)
+	_, err = PackSkill(pkg, &second, ArchiveZip)
+	require.NoError(t, err)
+	assert.Equal(t, first.Bytes(), second.Bytes(), "packing is reproducible")
+
+	assert.Equal(t, ManifestSpecVersion, m.SpecVersion)
+	assert.Equal(t, "1.0.0", m.Version)
+	require.Len(t, m.Files, 3)
+	assert.Equal(t, []string{"SKILL.md", "guide.md", "scripts/run.sh"}, []string{m.Files[0].Path, m.Files[1].Path, m.Files[2].Path})
+	assert.True(t, m.Files[2].Executable)
+	assert.Equal(t, pkg.Resources.Files[1].SHA256, m.Files[2].SHA256)
+
+	zr, err := zip.NewReader(bytes.NewReader(first.Bytes()), int64(first.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, "packer/"+ManifestFileName, zr.File[0].Name)
+
+	_, err = PackSkill(pkg, &bytes.Buffer{}, "rar")
+	assert.Error(t, err)
+}
+
+func TestInstallSkill(t *testing.T) {
+	work := t.TempDir()
+	skills := filepath.Join(t.TempDir(), "skills")
+
+	v1 := packTo(t, packTestSkill(t, filepath.Join(work, "v1"), "packer", "1.0.0"), work, ArchiveZip)
+	v2 := packTo(t, packTestSkill(t, filepath.Join(work, "v2"), "packer", "1.10.0"), work, ArchiveTarGz)
+
+	result, err := InstallSkill(v1, skills)
+	require.NoError(t, err)
+	assert.Equal(t, &InstallResult{Name: "packer", Version: "1.0.0", Path: filepath.Join(skills, "packer")}, result)
+	info, err := os.Stat(filepath.Join(skills, "packer", "scripts", "run.sh"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0o100, "executable bits survive packing")
+	m, err := ReadManifest(filepath.Join(skills, "packer"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", m.Version)
+
+	_, err = InstallSkill(v1, skills)
+	assert.ErrorContains(t, err, "already installed")
+
+	result, err = InstallSkill(v2, skills)
+	require.NoError(t, err)
+	assert.True(t, result.Replaced)
+	assert.Equal(t, "1.0.0", result.Previous)
+	assert.Equal(t, 1, result.VersionChange())
+	data, err := os.ReadFile(filepath.Join(skills, "packer", "scripts", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo 1.10.0", string(data))
+
+	_, err = InstallSkill(v1, skills)
+	assert.ErrorContains(t, err, "older than the installed version")
+	result, err = InstallSkill(v1, skills, WithForce())
+	require.NoError(t, err)
+	assert.Equal(t, -1, result.VersionChange(), "forcing an older version is a downgrade")
+	result, err = InstallSkill(v1, skills, WithForce())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.VersionChange())
+
+	entries, err := os.ReadDir(skills)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no staging or backup directories are left behind")
+}
+
+func TestInstallSkill_RejectsBadArchives(t *testing.T) {
+	work := t.TempDir()
+	pkg := packTestSkill(t, work, "packer", "1.0.0")
+	var good bytes.Buffer
+	_, err := PackSkill(pkg, &good, ArchiveZip)
+	require.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(good.Bytes()), int64(good.Len()))
+	require.NoError(t, err)
+
+	// rewrite copies the packed archive, letting edit rename or replace entries.
+	rewrite := func(edit func(name string, data []byte) (string, []byte)) string {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for _, zf := range zr.File {
+			rc, err := zf.Open()
+			require.NoError(t, err)
+			var data bytes.Buffer
+			_, err = data.ReadFrom(rc)
+			require.NoError(t, err)
+			rc.Close()
+			name, content := edit(zf.Name, data.Bytes())
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write(content)
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+		archive := filepath.Join(t.TempDir(), "bad.skill")
+		require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o644))
+		return archive
+	}
+
+	tests := []struct {
+		name string
+		edit func(string, []byte) (string, []byte)
+		want string
+	}{
+		{"traversal", func(n string, d []byte) (string, []byte) {
+			if n == "packer/guide.md" {
+				return "packer/../../guide.md", d
+			}
+			return n, d
+		}, "unsafe path"},
+		{"absolute", func(n string, d []byte) (string, []byte) {
+			if n == "packer/guide.md" {
+				return "/etc/guide.md", d
+			}
+			return n, d
+		}, "unsafe path"},
+		{"tampered", func(n string, d []byte) (string, []byte) {
+			if n == "packer/scripts/run.sh" {
+				return n, []byte("rm -rf ~")
+			}
+			return n, d
+		}, "does not match its manifest entry"},
+		{"renamed", func(n string, d []byte) (string, []byte) {
+			if n == "packer/guide.md" {
+				return "packer/extra.md", d
+			}
+			return n, d
+		}, "missing from the archive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skills := t.TempDir()
+			_, err := InstallSkill(rewrite(tt.edit), skills)
+			assert.ErrorContains(t, err, tt.want)
+			entries, err := os.ReadDir(skills)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, -1, compareVersions("1.2.0", "1.10.0"))
+	assert.Equal(t, 0, compareVersions("v1.2", "1.2.0"))
+	assert.Equal(t, -1, compareVersions("2.0.0-beta.2", "2.0.0"))
+	assert.Equal(t, 1, compareVersions("2.0.0-beta.10", "2.0.0-beta.2"))
+	assert.Equal(t, -1, compareVersions("", "0.1"))
+}
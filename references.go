@@ -0,0 +1,155 @@
+package goskills
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Reference sources.
+const (
+	ReferenceLink = "link" // A markdown link such as [forms](forms.md)
+	ReferenceCode = "code" // An inline code span such as `scripts/fill.py`
+)
+
+// FileReference is a mention of a file in the SKILL.md body.
+type FileReference struct {
+	Target  string `json:"target"`            // The reference as written in the body
+	Path    string `json:"path"`              // Target resolved against the skill root
+	Exists  bool   `json:"exists"`            // Whether Path exists inside the skill
+	Source  string `json:"source"`            // ReferenceLink or ReferenceCode
+	Section string `json:"section,omitempty"` // Heading of the section the reference appears in
+	Line    int    `json:"line"`              // Line in SKILL.md
+	Column  int    `json:"column"`            // Column in SKILL.md
+}
+
+var (
+	// markdownLinkPattern matches inline links and images; group 1 is the destination.
+	markdownLinkPattern = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'(][^)]*)?\)`)
+	// inlineCodePattern matches single-backtick code spans; group 1 is the content.
+	inlineCodePattern = regexp.MustCompile("`([^`\n]+)`")
+	// pathLikePattern matches strings that look like a relative file path with an extension.
+	pathLikePattern = regexp.MustCompile(`^[\w.-]+(?:/[\w.-]+)*\.[A-Za-z][A-Za-z0-9]{0,7}$`)
+	// headingPattern matches ATX headings; group 2 is the heading text.
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	// fencePattern matches the opening or closing line of a fenced code block.
+	fencePattern = regexp.MustCompile("^\\s{0,3}(`{3,}|~{3,})")
+)
+
+// extractReferences finds file references in a SKILL.md body. Every markdown
+// link is a reference; inline code spans count when they are a single
+// path-like token, or when a token inside a command (such as
+// `python scripts/fill.py`) names a file that exists. Fenced code blocks are
+// skipped. bodyLine is the line of SKILL.md on which body starts, and exists
+// reports whether a path relative to the skill root exists.
+func extractReferences(body string, bodyLine int, exists func(string) bool) []FileReference {
+	var refs []FileReference
+	section := ""
+	fence := ""
+
+	for i, line := range strings.Split(body, "\n") {
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			marker := m[1]
+			switch {
+			case fence == "":
+				fence = marker
+			case marker[0] == fence[0] && len(marker) >= len(fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			section = m[2]
+			continue
+		}
+
+		lineNo := bodyLine + i
+		add := func(target, source string, col int, mustExist bool) {
+			resolved, ok := resolveReference(target)
+			if !ok {
+				return
+			}
+			found := exists(resolved)
+			if mustExist && !found {
+				return
+			}
+			refs = append(refs, FileReference{
+				Target:  target,
+				Path:    resolved,
+				Exists:  found,
+				Source:  source,
+				Section: section,
+				Line:    lineNo,
+				Column:  col + 1,
+			})
+		}
+
+		// Links are blanked out once recorded so that a code span used as
+		// link text, as in [`forms.md`](forms.md), is not counted twice.
+		masked := []byte(line)
+		for _, m := range markdownLinkPattern.FindAllStringSubmatchIndex(line, -1) {
+			add(line[m[2]:m[3]], ReferenceLink, m[2], false)
+			for j := m[0]; j < m[1]; j++ {
+				masked[j] = ' '
+			}
+		}
+		for _, m := range inlineCodePattern.FindAllSubmatchIndex(masked, -1) {
+			code := line[m[2]:m[3]]
+			if pathLikePattern.MatchString(code) {
+				add(code, ReferenceCode, m[2], false)
+				continue
+			}
+			offset := 0
+			for _, token := range strings.Fields(code) {
+				offset += strings.Index(code[offset:], token)
+				if pathLikePattern.MatchString(token) {
+					add(token, ReferenceCode, m[2]+offset, true)
+				}
+				offset += len(token)
+			}
+		}
+	}
+
+	return refs
+}
+
+// resolveReference resolves a link destination or code path against the skill
+// root. It reports false for targets that are not local files: URLs, in-page
+// anchors and absolute paths.
+func resolveReference(target string) (string, bool) {
+	if strings.HasPrefix(target, "#") || strings.HasPrefix(target, "/") {
+		return "", false
+	}
+	if u, err := url.Parse(target); err != nil || u.Scheme != "" || u.Host != "" {
+		return "", false
+	}
+
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	target = strings.TrimPrefix(target, "./")
+	if target == "" {
+		return "", false
+	}
+	return path.Clean(target), true
+}
+
+// ReferencedFiles returns the distinct paths of the package's file references
+// that exist, in order of first appearance.
+func (p *SkillPackage) ReferencedFiles() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, ref := range p.References {
+		if ref.Exists && !seen[ref.Path] {
+			seen[ref.Path] = true
+			files = append(files, ref.Path)
+		}
+	}
+	return files
+}
@@ -0,0 +1,459 @@
+package goskills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Root scopes, from highest to lowest default precedence.
+const (
+	ScopeProject = "project" // Skills checked into the current project
+	ScopeUser    = "user"    // Skills installed for the current user
+	ScopeSystem  = "system"  // Skills installed for every user of the machine
+)
+
+// Root is a directory searched for skill packages.
+type Root struct {
+	Path     string `json:"path"`
+	Scope    string `json:"scope"`              // ScopeProject, ScopeUser, ScopeSystem or a caller-defined label
+	Optional bool   `json:"optional,omitempty"` // A missing optional root is skipped instead of reported as an error
+}
+
+// DefaultRoots returns the conventional skill roots in order of precedence:
+// .goskills/skills in projectDir, goskills/skills in the user configuration
+// directory (~/.config on Linux) and /etc/goskills/skills. All of them are optional.
+func DefaultRoots(projectDir string) []Root {
+	roots := []Root{{Path: filepath.Join(projectDir, ".goskills", "skills"), Scope: ScopeProject, Optional: true}}
+	if dir, err := os.UserConfigDir(); err == nil {
+		roots = append(roots, Root{Path: filepath.Join(dir, "goskills", "skills"), Scope: ScopeUser, Optional: true})
+	}
+	if filepath.Separator == '/' {
+		roots = append(roots, Root{Path: "/etc/goskills/skills", Scope: ScopeSystem, Optional: true})
+	}
+	return roots
+}
+
+// RegisteredSkill is a skill known to a Registry, together with the root it was found in.
+type RegisteredSkill struct {
+	*SkillIndex
+	Root Root `json:"root"`
+}
+
+// Collision kinds.
+const (
+	// CollisionShadowed means a skill in a higher-precedence root hides
+	// skills of the same name in lower-precedence roots. This is how a
+	// project or user overrides a system skill.
+	CollisionShadowed = "shadowed"
+	// CollisionDuplicate means several skills in the same root share a
+	// name. The first by path wins, but the clash is most likely a mistake.
+	CollisionDuplicate = "duplicate"
+)
+
+// Collision describes several skills that share a name. Active is the skill
+// the name resolves to; Hidden are the skills it hides, in precedence order.
+type Collision struct {
+	Name   string             `json:"name"`
+	Kind   string             `json:"kind"` // CollisionShadowed or CollisionDuplicate
+	Active *RegisteredSkill   `json:"active"`
+	Hidden []*RegisteredSkill `json:"hidden"`
+}
+
+// Registry holds the skills found in an ordered list of roots. A name always
+// resolves to a single skill: roots earlier in the list shadow later ones, and
+// within a root the skill with the lexically first path wins. Every clash is
+// recorded as a Collision. A Registry is safe for concurrent use, which lets
+// Watch keep it up to date while it is being queried.
+type Registry struct {
+	roots []Root
+	opts  *parseOptions
+
+	syncMu     sync.Mutex // Serializes sync
+	mu         sync.RWMutex
+	all        []*RegisteredSkill   // Every indexed skill, including hidden ones, in root and path order
+	stamps     map[string]fileStamp // SKILL.md state of each skill directory when it was last indexed
+	failed     map[string]error     // Parse errors of skill directories whose current SKILL.md does not parse
+	loaded     map[string]*SkillPackage
+	skills     []*RegisteredSkill // Active skills
+	byName     map[string]*RegisteredSkill
+	collisions []Collision
+	failures   []ParseFailure
+}
+
+// fileStamp identifies a version of a file without reading it.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewRegistry indexes the skills in roots, reading only their frontmatter.
+// Options are applied as in IndexSkillPackages; with WithStrict, duplicate
+// names within a root are reported as an error as well.
+func NewRegistry(roots []Root, opts ...ParseOption) (*Registry, error) {
+	r := &Registry{
+		opts:   newParseOptions(opts),
+		stamps: make(map[string]fileStamp),
+		loaded: make(map[string]*SkillPackage),
+	}
+	for _, root := range roots {
+		abs, err := filepath.Abs(root.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve skill root %s: %w", root.Path, err)
+		}
+		root.Path = abs
+		r.roots = append(r.roots, root)
+	}
+
+	if _, err := r.sync(nil, true); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// sync brings the registry in line with the roots on disk. Only skill
+// directories whose SKILL.md changed since the last sync, or that contain a
+// path in dirty, are indexed again; a directory whose SKILL.md no longer
+// parses keeps its previous entry, and so does every skill of a root that
+// cannot be walked. It returns an event per change. During the
+// initial load, strict mode and missing required roots are reported as errors.
+func (r *Registry) sync(dirty map[string]bool, initial bool) ([]RegistryEvent, error) {
+	r.syncMu.Lock()
+	defer r.syncMu.Unlock()
+
+	r.mu.RLock()
+	prevAll, prevStamps, prevFailed := r.all, r.stamps, r.failed
+	r.mu.RUnlock()
+	previous := make(map[string]*RegisteredSkill, len(prevAll))
+	for _, s := range prevAll {
+		previous[s.Path] = s
+	}
+
+	// Find the skill directories and decide which of them to index again.
+	type candidate struct {
+		root  Root
+		src   skillSource
+		dir   string
+		stamp fileStamp
+		old   *RegisteredSkill
+	}
+	var (
+		candidates []*candidate
+		changed    []string // Display paths of candidates to index
+		byPath     = make(map[string]*candidate)
+	)
+	for _, root := range r.roots {
+		info, err := os.Stat(root.Path)
+		if err != nil || !info.IsDir() {
+			if !initial || root.Optional && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err == nil {
+				err = fmt.Errorf("not a directory")
+			}
+			return nil, fmt.Errorf("skill root %s: %w", root.Path, err)
+		}
+
+		src := osSource(root.Path)
+		dirs, err := findSkillDirs(context.Background(), src, ".", r.opts)
+		if err != nil {
+			if initial {
+				return nil, err
+			}
+			// Keep the root's previous entries, unchanged, until a walk
+			// succeeds again.
+			var kept []string
+			for dirPath := range prevStamps {
+				rel, err := filepath.Rel(root.Path, dirPath)
+				if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && byPath[dirPath] == nil {
+					kept = append(kept, dirPath)
+				}
+			}
+			sort.Strings(kept)
+			for _, dirPath := range kept {
+				rel, _ := filepath.Rel(root.Path, dirPath)
+				c := &candidate{root: root, src: src, dir: filepath.ToSlash(rel), stamp: prevStamps[dirPath], old: previous[dirPath]}
+				candidates = append(candidates, c)
+				byPath[dirPath] = c
+			}
+			continue
+		}
+		for _, dir := range dirs {
+			dirPath := src.displayPath(dir)
+			if byPath[dirPath] != nil {
+				continue // Already found through an earlier, overlapping root
+			}
+			c := &candidate{root: root, src: src, dir: dir, stamp: skillStamp(dirPath), old: previous[dirPath]}
+			candidates = append(candidates, c)
+			byPath[dirPath] = c
+			if prevStamp, ok := prevStamps[dirPath]; !ok || prevStamp != c.stamp || isDirty(dirPath, dirty) {
+				changed = append(changed, dirPath)
+			}
+		}
+	}
+
+	indexed, errs := parallelParse(context.Background(), changed, r.opts.concurrency, func(ctx context.Context, dirPath string) (*SkillIndex, error) {
+		c := byPath[dirPath]
+		return indexSkillPackage(c.src, c.dir, r.opts)
+	})
+	results := make(map[string]int, len(changed))
+	for i, dirPath := range changed {
+		results[dirPath] = i
+	}
+
+	var (
+		all      []*RegisteredSkill
+		failures []ParseFailure
+		events   []RegistryEvent
+		stamps   = make(map[string]fileStamp, len(candidates))
+		failed   = make(map[string]error)
+	)
+	for _, c := range candidates {
+		dirPath := c.src.displayPath(c.dir)
+		stamps[dirPath] = c.stamp
+
+		i, reindexed := results[dirPath]
+		if !reindexed {
+			if c.old != nil {
+				all = append(all, c.old)
+			}
+			if err, ok := prevFailed[dirPath]; ok {
+				failed[dirPath] = err
+				failures = append(failures, ParseFailure{Dir: dirPath, Err: err})
+			}
+			continue
+		}
+
+		if err := errs[i]; err != nil {
+			failure := ParseFailure{Dir: dirPath, Err: err}
+			if initial && r.opts.strict {
+				return nil, fmt.Errorf("failed to index skill package: %w", failure)
+			}
+			failed[dirPath] = err
+			failures = append(failures, failure)
+			if c.old != nil {
+				// Keep serving the last version that parsed.
+				all = append(all, c.old)
+				events = append(events, RegistryEvent{Type: SkillFailed, Name: c.old.Meta.Name, Path: dirPath, Skill: c.old, Err: err})
+			} else if !initial {
+				events = append(events, RegistryEvent{Type: SkillFailed, Path: dirPath, Err: err})
+			}
+			continue
+		}
+
+		s := &RegisteredSkill{SkillIndex: indexed[i], Root: c.root}
+		all = append(all, s)
+		if c.old == nil {
+			events = append(events, RegistryEvent{Type: SkillAdded, Name: s.Meta.Name, Path: dirPath, Skill: s})
+		} else {
+			events = append(events, RegistryEvent{Type: SkillUpdated, Name: s.Meta.Name, Path: dirPath, Skill: s})
+		}
+	}
+	for _, old := range prevAll {
+		if byPath[old.Path] == nil {
+			events = append(events, RegistryEvent{Type: SkillRemoved, Name: old.Meta.Name, Path: old.Path, Skill: old})
+		}
+	}
+
+	byName, skills, collisions := resolveNames(all)
+	if initial && r.opts.strict {
+		for _, c := range collisions {
+			if c.Kind == CollisionDuplicate {
+				return nil, fmt.Errorf("duplicate skill name '%s' in %s", c.Name, c.Active.Root.Path)
+			}
+		}
+	}
+	if r.opts.sortByName {
+		sort.SliceStable(skills, func(i, j int) bool {
+			return skills[i].Meta.Name < skills[j].Meta.Name
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.all, r.stamps, r.failed, r.failures = all, stamps, failed, failures
+	r.byName, r.skills, r.collisions = byName, skills, collisions
+	for path := range r.loaded {
+		if byPath[path] == nil {
+			delete(r.loaded, path)
+		}
+	}
+	return events, nil
+}
+
+// skillStamp returns the current stamp of the SKILL.md in dir, or the zero stamp if it cannot be read.
+func skillStamp(dir string) fileStamp {
+	info, err := os.Stat(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// isDirty reports whether dir or a path below it is in dirty.
+func isDirty(dir string, dirty map[string]bool) bool {
+	for p := range dirty {
+		if p == dir || strings.HasPrefix(p, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveNames picks the active skill for every name and records the collisions, sorted by name.
+func resolveNames(all []*RegisteredSkill) (map[string]*RegisteredSkill, []*RegisteredSkill, []Collision) {
+	byName := make(map[string]*RegisteredSkill)
+	var skills []*RegisteredSkill
+	collisions := make(map[string]*Collision)
+	var names []string
+	for _, s := range all {
+		name := s.Meta.Name
+		active, taken := byName[name]
+		if !taken {
+			byName[name] = s
+			skills = append(skills, s)
+			continue
+		}
+		c, ok := collisions[name]
+		if !ok {
+			c = &Collision{Name: name, Kind: CollisionShadowed, Active: active}
+			collisions[name] = c
+			names = append(names, name)
+		}
+		if s.Root == active.Root {
+			c.Kind = CollisionDuplicate
+		}
+		c.Hidden = append(c.Hidden, s)
+	}
+
+	sort.Strings(names)
+	sorted := make([]Collision, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, *collisions[name])
+	}
+	return byName, skills, sorted
+}
+
+// Roots returns the roots searched by the registry, in order of precedence, with absolute paths.
+func (r *Registry) Roots() []Root {
+	return r.roots
+}
+
+// Skills returns the active skills: one per name, in root order and then by
+// path, or by name when the registry was built with WithSortByName.
+func (r *Registry) Skills() []*RegisteredSkill {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*RegisteredSkill(nil), r.skills...)
+}
+
+// Lookup returns the active skill with the given name.
+func (r *Registry) Lookup(name string) (*RegisteredSkill, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.byName[name]
+	return s, ok
+}
+
+// LookupPath returns the skill in the directory dir, whether it is active or
+// hidden by a collision.
+func (r *Registry) LookupPath(dir string) (*RegisteredSkill, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.all {
+		if s.Path == abs {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// WithTag returns the active skills carrying tag, compared case-insensitively.
+func (r *Registry) WithTag(tag string) []*RegisteredSkill {
+	var skills []*RegisteredSkill
+	for _, s := range r.Skills() {
+		for _, t := range s.Meta.Tags() {
+			if strings.EqualFold(t, tag) {
+				skills = append(skills, s)
+				break
+			}
+		}
+	}
+	return skills
+}
+
+// Collisions returns the names shared by more than one skill, sorted by name.
+func (r *Registry) Collisions() []Collision {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Collision(nil), r.collisions...)
+}
+
+// Failures returns the skill directories whose frontmatter could not be
+// parsed. This includes skills that are still served in their last good
+// version after an edit broke them.
+func (r *Registry) Failures() []ParseFailure {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]ParseFailure(nil), r.failures...)
+}
+
+// Load parses the full package of the active skill with the given name. If the
+// package on disk no longer parses, the last version loaded successfully
+// through the registry is returned instead, so that a bad edit does not take a
+// skill away from a running service.
+func (r *Registry) Load(name string) (*SkillPackage, error) {
+	s, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("skill not found: %s", name)
+	}
+	pkg, err := s.Load()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		if last, ok := r.loaded[s.Path]; ok {
+			return last, nil
+		}
+		return nil, err
+	}
+	r.loaded[s.Path] = pkg
+	return pkg, nil
+}
+
+// Tags returns the skill's tags. They are read from a top-level 'tags' field,
+// given as a list or a comma-separated string, or else from the 'tags' entry
+// of metadata.
+func (m SkillMeta) Tags() []string {
+	var raw []string
+	switch v := m.Extra["tags"].(type) {
+	case []interface{}:
+		for _, t := range v {
+			raw = append(raw, fmt.Sprint(t))
+		}
+	case string:
+		raw = strings.Split(v, ",")
+	default:
+		if v, ok := m.Metadata["tags"]; ok {
+			raw = strings.Split(v, ",")
+		}
+	}
+
+	var tags []string
+	for _, t := range raw {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
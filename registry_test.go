@@ -0,0 +1,124 @@
+package goskills
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSkill creates a SKILL.md in dir with the given frontmatter lines.
+func writeSkill(t *testing.T, dir string, frontmatter string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	content := "---\n" + frontmatter + "\n---\n# Body\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+}
+
+func TestRegistry(t *testing.T) {
+	project := t.TempDir()
+	user := t.TempDir()
+	writeSkill(t, filepath.Join(project, "pdf"), "name: pdf\ndescription: Project PDF.\ntags: [documents, forms]")
+	writeSkill(t, filepath.Join(project, "a", "notes"), "name: notes\ndescription: First notes.")
+	writeSkill(t, filepath.Join(project, "b", "notes"), "name: notes\ndescription: Second notes.")
+	writeSkill(t, filepath.Join(user, "pdf"), "name: pdf\ndescription: User PDF.")
+	writeSkill(t, filepath.Join(user, "xlsx"), "name: xlsx\ndescription: Sheets.\nmetadata:\n  tags: documents, spreadsheets")
+
+	registry, err := NewRegistry([]Root{
+		{Path: project, Scope: ScopeProject},
+		{Path: user, Scope: ScopeUser},
+		{Path: filepath.Join(user, "missing"), Scope: ScopeSystem, Optional: true},
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for _, s := range registry.Skills() {
+		names = append(names, s.Meta.Name)
+	}
+	assert.Equal(t, []string{"notes", "pdf", "xlsx"}, names)
+
+	pdf, ok := registry.Lookup("pdf")
+	require.True(t, ok)
+	assert.Equal(t, "Project PDF.", pdf.Meta.Description)
+	assert.Equal(t, ScopeProject, pdf.Root.Scope)
+	_, ok = registry.Lookup("docx")
+	assert.False(t, ok)
+
+	require.Len(t, registry.Collisions(), 2)
+	notes := registry.Collisions()[0]
+	assert.Equal(t, "notes", notes.Name)
+	assert.Equal(t, CollisionDuplicate, notes.Kind)
+	assert.Equal(t, filepath.Join(project, "a", "notes"), notes.Active.Path)
+	require.Len(t, notes.Hidden, 1)
+	assert.Equal(t, filepath.Join(project, "b", "notes"), notes.Hidden[0].Path)
+	shadowed := registry.Collisions()[1]
+	assert.Equal(t, CollisionShadowed, shadowed.Kind)
+	assert.Equal(t, "User PDF.", shadowed.Hidden[0].Meta.Description)
+
+	hidden, ok := registry.LookupPath(filepath.Join(user, "pdf"))
+	require.True(t, ok)
+	assert.Equal(t, ScopeUser, hidden.Root.Scope)
+
+	tagged := registry.WithTag("Documents")
+	require.Len(t, tagged, 2)
+	assert.Equal(t, "pdf", tagged[0].Meta.Name)
+	assert.Equal(t, "xlsx", tagged[1].Meta.Name)
+
+	pkg, err := pdf.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "# Body", pkg.Body)
+
+	_, err = NewRegistry([]Root{{Path: project}}, WithStrict())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate skill name 'notes'")
+
+	_, err = NewRegistry([]Root{{Path: filepath.Join(user, "missing")}})
+	assert.Error(t, err)
+}
+
+func TestRegistry_Dependencies(t *testing.T) {
+	root := t.TempDir()
+	writeSkill(t, filepath.Join(root, "ooxml"), "name: ooxml\ndescription: Shared OOXML material.\nversion: 1.4.0")
+	writeSkill(t, filepath.Join(root, "docx"), "name: docx\ndescription: Word documents.\nrequires:\n  - ooxml ^1.2\n  - name: fonts\n    version: '>=2.0.0'")
+	writeSkill(t, filepath.Join(root, "pptx"), "name: pptx\ndescription: Slides.\nrequires: [ooxml ~1.4, docx]")
+	writeSkill(t, filepath.Join(root, "legacy"), "name: legacy\ndescription: Old slides.\nrequires: [ooxml ^2.0.0]")
+	writeSkill(t, filepath.Join(root, "chicken"), "name: chicken\ndescription: Cycle.\nrequires: [egg]")
+	writeSkill(t, filepath.Join(root, "egg"), "name: egg\ndescription: Cycle.\nrequires: [chicken]")
+
+	registry, err := NewRegistry([]Root{{Path: root, Scope: ScopeUser}})
+	require.NoError(t, err)
+
+	docx, ok := registry.Lookup("docx")
+	require.True(t, ok)
+	assert.Equal(t, []Dependency{{Name: "ooxml", Version: "^1.2"}, {Name: "fonts", Version: ">=2.0.0"}}, docx.Meta.Requires)
+
+	deps, problems, err := registry.Dependencies("pptx")
+	require.NoError(t, err)
+	var names []string
+	for _, d := range deps {
+		names = append(names, d.Meta.Name)
+	}
+	assert.Equal(t, []string{"ooxml", "docx"}, names, "dependencies come before the skills that require them")
+	require.Len(t, problems, 1)
+	assert.Equal(t, DependencyMissing, problems[0].Kind)
+	assert.Equal(t, "docx", problems[0].Skill)
+	assert.Equal(t, "skill 'docx' requires 'fonts >=2.0.0', which is not installed", problems[0].String())
+
+	deps, problems, err = registry.Dependencies("legacy")
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+	require.Len(t, problems, 1)
+	assert.Equal(t, DependencyConflict, problems[0].Kind)
+	assert.Contains(t, problems[0].String(), "version 1.4.0")
+
+	_, _, err = registry.Dependencies("missing")
+	assert.Error(t, err)
+
+	var kinds []string
+	for _, p := range registry.DependencyProblems() {
+		kinds = append(kinds, p.Skill+":"+p.Kind)
+	}
+	assert.Equal(t, []string{"docx:missing", "egg:cycle", "legacy:conflict"}, kinds)
+}
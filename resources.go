@@ -0,0 +1,227 @@
+package goskills
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ResourceKind classifies a file bundled with a skill.
+type ResourceKind string
+
+const (
+	KindScript    ResourceKind = "script"    // Executable code the skill may run
+	KindReference ResourceKind = "reference" // Documentation loaded into context as needed
+	KindAsset     ResourceKind = "asset"     // Files used in output: images, fonts, schemas, data
+	KindTemplate  ResourceKind = "template"  // Boilerplate copied or filled in by the skill
+	KindLicense   ResourceKind = "license"   // License terms of the skill
+	KindOther     ResourceKind = "other"     // Anything else, such as dependency manifests
+)
+
+// ResourceFile is a single file in the skill package inventory.
+type ResourceFile struct {
+	Path       string       `json:"path"` // Path relative to the skill root
+	Kind       ResourceKind `json:"kind"`
+	Size       int64        `json:"size"`
+	MIME       string       `json:"mime,omitempty"`   // Media type from the extension, or sniffed from the content
+	SHA256     string       `json:"sha256,omitempty"` // Hex-encoded digest of the content; empty when it was not read
+	Mode       fs.FileMode  `json:"mode"`
+	Executable bool         `json:"executable,omitempty"` // Any execute permission bit is set
+	Symlink    bool         `json:"symlink,omitempty"`    // The entry is a symbolic link; Size, Mode and the digest describe its target
+	// EscapesRoot is set for a symbolic link that resolves outside the skill
+	// root. The content of such a file is not read.
+	EscapesRoot bool      `json:"escapes_root,omitempty"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// resourceDirKinds maps conventional top-level directory names to the kind of the files they hold.
+var resourceDirKinds = map[string]ResourceKind{
+	"scripts":    KindScript,
+	"script":     KindScript,
+	"bin":        KindScript,
+	"references": KindReference,
+	"reference":  KindReference,
+	"docs":       KindReference,
+	"examples":   KindReference,
+	"assets":     KindAsset,
+	"templates":  KindTemplate,
+	"template":   KindTemplate,
+}
+
+// scriptExtensions lists file extensions of code. Only code under a script
+// directory is KindScript; elsewhere it is KindOther, such as helper modules
+// imported by the scripts, and never becomes a tool.
+var scriptExtensions = map[string]bool{
+	".py": true, ".sh": true, ".bash": true, ".zsh": true,
+	".js": true, ".mjs": true, ".cjs": true, ".ts": true,
+	".rb": true, ".pl": true, ".php": true, ".lua": true,
+	".go": true, ".ps1": true, ".r": true,
+}
+
+// referenceExtensions lists file extensions of documentation.
+var referenceExtensions = map[string]bool{
+	".md": true, ".markdown": true, ".txt": true, ".rst": true, ".adoc": true,
+}
+
+// templateExtensions lists file extensions of template formats.
+var templateExtensions = map[string]bool{
+	".tmpl": true, ".tpl": true, ".j2": true, ".jinja": true,
+	".hbs": true, ".mustache": true,
+}
+
+// manifestFiles lists dependency and build manifests, which are classified as KindOther.
+var manifestFiles = map[string]bool{
+	"requirements.txt":  true,
+	"package.json":      true,
+	"package-lock.json": true,
+	"pyproject.toml":    true,
+	"go.mod":            true,
+	"go.sum":            true,
+	"Makefile":          true,
+}
+
+// classifyResource determines the kind of a file from its slash-separated path
+// relative to the skill root. A conventional top-level directory (scripts/,
+// references/, assets/, templates/ and their common aliases) decides the kind
+// of everything beneath it; otherwise the file name and extension do. Only
+// files under scripts/, script/ or bin/ are KindScript: code elsewhere is
+// KindOther. Sidecar script specs are KindOther wherever they are.
+func classifyResource(rel string) ResourceKind {
+	base := path.Base(rel)
+	upper := strings.ToUpper(base)
+	if !strings.Contains(rel, "/") && (strings.HasPrefix(upper, "LICENSE") || strings.HasPrefix(upper, "LICENCE") || strings.HasPrefix(upper, "COPYING")) {
+		return KindLicense
+	}
+	if strings.HasSuffix(base, ScriptSpecSuffix) {
+		return KindOther
+	}
+
+	if top, _, nested := strings.Cut(rel, "/"); nested {
+		if kind, ok := resourceDirKinds[top]; ok {
+			return kind
+		}
+	}
+
+	if manifestFiles[base] {
+		return KindOther
+	}
+	ext := strings.ToLower(path.Ext(base))
+	switch {
+	case scriptExtensions[ext]:
+		return KindOther
+	case referenceExtensions[ext]:
+		return KindReference
+	case templateExtensions[ext]:
+		return KindTemplate
+	case ext == "":
+		return KindOther
+	default:
+		return KindAsset
+	}
+}
+
+// mimeTypes maps extensions common in skills that the system MIME tables may
+// not know, or may map differently from one machine to the next.
+var mimeTypes = map[string]string{
+	".md":       "text/markdown; charset=utf-8",
+	".markdown": "text/markdown; charset=utf-8",
+	".txt":      "text/plain; charset=utf-8",
+	".rst":      "text/x-rst; charset=utf-8",
+	".adoc":     "text/asciidoc; charset=utf-8",
+	".py":       "text/x-python; charset=utf-8",
+	".sh":       "text/x-shellscript; charset=utf-8",
+	".bash":     "text/x-shellscript; charset=utf-8",
+	".zsh":      "text/x-shellscript; charset=utf-8",
+	".js":       "text/javascript; charset=utf-8",
+	".mjs":      "text/javascript; charset=utf-8",
+	".cjs":      "text/javascript; charset=utf-8",
+	".ts":       "text/x-typescript; charset=utf-8",
+	".rb":       "text/x-ruby; charset=utf-8",
+	".pl":       "text/x-perl; charset=utf-8",
+	".go":       "text/x-go; charset=utf-8",
+	".json":     "application/json",
+	".yaml":     "application/yaml",
+	".yml":      "application/yaml",
+	".toml":     "application/toml",
+	".xml":      "application/xml",
+	".xsd":      "application/xml",
+	".html":     "text/html; charset=utf-8",
+	".css":      "text/css; charset=utf-8",
+	".csv":      "text/csv; charset=utf-8",
+	".pdf":      "application/pdf",
+	".png":      "image/png",
+	".jpg":      "image/jpeg",
+	".jpeg":     "image/jpeg",
+	".gif":      "image/gif",
+	".svg":      "image/svg+xml",
+	".ttf":      "font/ttf",
+	".otf":      "font/otf",
+	".woff":     "font/woff",
+	".woff2":    "font/woff2",
+	".zip":      "application/zip",
+}
+
+// detectMIME returns the media type of a file from its extension, falling
+// back to sniffing head, the start of its content, when the extension is
+// unknown. It returns "" when neither is available.
+func detectMIME(name string, head []byte) string {
+	ext := strings.ToLower(path.Ext(name))
+	if t, ok := mimeTypes[ext]; ok {
+		return t
+	}
+	if ext != "" {
+		if t := mime.TypeByExtension(ext); t != "" {
+			return t
+		}
+	}
+	if head == nil {
+		return ""
+	}
+	return http.DetectContentType(head)
+}
+
+// resourcesFromFiles builds SkillResources from an inventory, deriving the typed lists as views over it.
+func resourcesFromFiles(files []ResourceFile) SkillResources {
+	res := SkillResources{Files: files}
+	for _, f := range files {
+		switch f.Kind {
+		case KindScript:
+			res.Scripts = append(res.Scripts, f.Path)
+		case KindReference:
+			res.References = append(res.References, f.Path)
+		case KindAsset:
+			res.Assets = append(res.Assets, f.Path)
+		case KindTemplate:
+			res.Templates = append(res.Templates, f.Path)
+		}
+	}
+	return res
+}
+
+// Without returns the resources with the files of the given kinds left out
+// of the inventory and of the typed lists. Leaving out KindScript keeps the
+// scripts of a skill from becoming tools.
+func (r SkillResources) Without(kinds ...ResourceKind) SkillResources {
+	var files []ResourceFile
+	for _, f := range r.Files {
+		if !slices.Contains(kinds, f.Kind) {
+			files = append(files, f)
+		}
+	}
+	return resourcesFromFiles(files)
+}
+
+// FilesOfKind returns the inventory entries of the given kind.
+func (r SkillResources) FilesOfKind(kind ResourceKind) []ResourceFile {
+	var files []ResourceFile
+	for _, f := range r.Files {
+		if f.Kind == kind {
+			files = append(files, f)
+		}
+	}
+	return files
+}
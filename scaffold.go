@@ -0,0 +1,191 @@
+package goskills
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderDescription is the description of a new skill created without one.
+const placeholderDescription = "TODO: Describe what this skill does and when to use it."
+
+// scaffoldFile is a file to be written into a new skill.
+type scaffoldFile struct {
+	data       []byte
+	executable bool
+}
+
+// scaffoldExample is the example file CreateSkill puts in a resource directory.
+// Its content is a format string given the skill name and title.
+type scaffoldExample struct {
+	name       string
+	content    string
+	executable bool
+	summary    string // Description of the directory in the SKILL.md body
+}
+
+// scaffoldExamples maps the resource directories CreateSkill can create to their example files.
+var scaffoldExamples = map[string]scaffoldExample{
+	"scripts": {
+		name:       "example.py",
+		content:    "#!/usr/bin/env python3\n\"\"\"Example script for the %[1]s skill. Replace it or delete it.\"\"\"\n\n\ndef main():\n    print(\"Example script for %[1]s\")\n\n\nif __name__ == \"__main__\":\n    main()\n",
+		executable: true,
+		summary:    "executable code that performs specific operations",
+	},
+	"references": {
+		name:    "reference.md",
+		content: "# %[2]s Reference\n\nTODO: Add detailed documentation that SKILL.md points to, or delete this file.\n",
+		summary: "documentation loaded into context when the task needs it",
+	},
+	"assets": {
+		name:    "example.txt",
+		content: "TODO: Replace with files used in the output of the %[1]s skill, such as images or fonts.\n",
+		summary: "files used in the output, such as images and fonts",
+	},
+	"templates": {
+		name:    "example.tmpl",
+		content: "TODO: Replace with boilerplate that the %[1]s skill copies or fills in.\n",
+		summary: "boilerplate copied or filled in by the skill",
+	},
+}
+
+// CreateSkill creates a new skill named name in parent/name and returns it
+// parsed. By default the skill has only a SKILL.md with placeholder
+// instructions; options set its frontmatter, add resource directories with
+// example files and a LICENSE.txt, or start it from a template skill. The
+// skill is assembled in a temporary directory and moved into place, so a
+// failure leaves nothing behind. It fails if parent/name already exists.
+func CreateSkill(parent, name string, opts ...ScaffoldOption) (*SkillPackage, error) {
+	o := newScaffoldOptions(opts)
+	if err := ValidateSkillName(name); err != nil {
+		return nil, err
+	}
+	for _, dir := range o.resources {
+		if _, ok := scaffoldExamples[dir]; !ok {
+			return nil, fmt.Errorf("unknown resource directory '%s' (expected scripts, references, assets or templates)", dir)
+		}
+	}
+	target := filepath.Join(parent, name)
+	if _, err := os.Lstat(target); err == nil {
+		return nil, fmt.Errorf("%s already exists", target)
+	}
+
+	title := skillTitle(name)
+	meta := SkillMeta{Name: name, Description: placeholderDescription}
+	body := defaultSkillBody(title, o.resources)
+	files := make(map[string]scaffoldFile)
+	if o.template != "" {
+		tmpl, err := ParseSkillPackage(o.template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		meta, body = tmpl.Meta, tmpl.Body
+		meta.Name = name
+		for _, f := range tmpl.Resources.Files {
+			if !tmpl.contentAllowed(f) {
+				return nil, fmt.Errorf("template file %s: %w", f.Path, errLinkEscapes)
+			}
+			data, err := tmpl.ReadResource(f.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template file %s: %w", f.Path, err)
+			}
+			files[filepath.ToSlash(f.Path)] = scaffoldFile{data: data, executable: f.Executable}
+		}
+	}
+	if o.description != "" {
+		meta.Description = o.description
+	}
+	if len(o.allowedTools) > 0 {
+		meta.AllowedTools = o.allowedTools
+	}
+	if o.license != "" {
+		meta.License = o.license + ". LICENSE.txt has complete terms"
+		files["LICENSE.txt"] = scaffoldFile{data: []byte(fmt.Sprintf("TODO: Replace this file with the complete terms of the %s license.\n", o.license))}
+	}
+	for _, dir := range o.resources {
+		if hasFilesIn(files, dir) {
+			continue
+		}
+		ex := scaffoldExamples[dir]
+		files[dir+"/"+ex.name] = scaffoldFile{data: []byte(fmt.Sprintf(ex.content, name, title)), executable: ex.executable}
+	}
+
+	frontmatter, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	files["SKILL.md"] = scaffoldFile{data: []byte("---\n" + string(frontmatter) + "---\n\n" + strings.TrimSpace(body) + "\n")}
+
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(parent, ".new-"+name+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+	if err := os.Chmod(staging, 0o755); err != nil {
+		return nil, err
+	}
+	for rel, f := range files {
+		path := filepath.Join(staging, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, f.data, packedMode(f.executable)); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.Rename(staging, target); err != nil {
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+	return ParseSkillPackage(target)
+}
+
+// hasFilesIn reports whether any of files lies under the directory dir.
+func hasFilesIn(files map[string]scaffoldFile, dir string) bool {
+	for rel := range files {
+		if strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// skillTitle turns a hyphen-case skill name into a title: "pdf-tools" becomes "Pdf Tools".
+func skillTitle(name string) string {
+	words := strings.Split(name, "-")
+	for i, w := range words {
+		r := []rune(w)
+		if len(r) > 0 {
+			r[0] = unicode.ToUpper(r[0])
+		}
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+// defaultSkillBody returns the placeholder SKILL.md body of a new skill.
+func defaultSkillBody(title string, resources []string) string {
+	var sb strings.Builder
+	sb.WriteString("# " + title + "\n\n")
+	sb.WriteString("## Overview\n\n")
+	sb.WriteString("TODO: Explain what this skill enables and the steps to follow when using it.\n")
+	if len(resources) > 0 {
+		dirs := append([]string(nil), resources...)
+		sort.Strings(dirs)
+		sb.WriteString("\n## Resources\n\n")
+		for i, dir := range dirs {
+			if i > 0 && dirs[i-1] == dir {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("- `%s/`: %s.\n", dir, scaffoldExamples[dir].summary))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package goskills
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSkill(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "skills")
+
+	pkg, err := CreateSkill(parent, "report-builder",
+		WithDescription("Builds reports: use it for weekly summaries."),
+		WithAllowedTools("read_file", "run_shell_code"),
+		WithResourceDirs("scripts", "references", "assets"),
+		WithLicense("MIT"))
+	require.NoError(t, err)
+	assert.Equal(t, "report-builder", pkg.Meta.Name)
+	assert.Equal(t, "Builds reports: use it for weekly summaries.", pkg.Meta.Description)
+	assert.Equal(t, []string{"read_file", "run_shell_code"}, pkg.Meta.AllowedTools)
+	assert.Equal(t, []string{"scripts/example.py"}, pkg.Resources.Scripts)
+	assert.Equal(t, []string{"references/reference.md"}, pkg.Resources.References)
+	assert.Len(t, pkg.Resources.FilesOfKind(KindLicense), 1)
+	assert.True(t, pkg.Resources.Files[len(pkg.Resources.Files)-1].Executable)
+	assert.NotNil(t, pkg.Document.Section("resources"))
+	assert.Empty(t, Validate(pkg), "a new skill is spec-compliant")
+
+	_, err = CreateSkill(parent, "report-builder")
+	assert.ErrorContains(t, err, "already exists")
+	_, err = CreateSkill(parent, "Report_Builder")
+	assert.Error(t, err)
+	_, err = CreateSkill(parent, "other", WithResourceDirs("bin"))
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "failed attempts leave nothing behind")
+}
+
+func TestCreateSkill_FromTemplate(t *testing.T) {
+	parent := t.TempDir()
+	template := filepath.Join(parent, "base")
+	writeSkill(t, template, "name: base\ndescription: Base skill.\nmetadata:\n  team: docs")
+	require.NoError(t, os.MkdirAll(filepath.Join(template, "scripts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(template, "scripts", "build.sh"), []byte("make"), 0o755))
+
+	pkg, err := CreateSkill(parent, "derived", WithTemplate(template), WithResourceDirs("scripts", "references"))
+	require.NoError(t, err)
+	assert.Equal(t, "derived", pkg.Meta.Name)
+	assert.Equal(t, "Base skill.", pkg.Meta.Description)
+	assert.Equal(t, map[string]string{"team": "docs"}, pkg.Meta.Metadata)
+	assert.Equal(t, "# Body", pkg.Body)
+	assert.Equal(t, []string{"scripts/build.sh"}, pkg.Resources.Scripts, "template scripts replace the example")
+	assert.Equal(t, []string{"references/reference.md"}, pkg.Resources.References)
+}
@@ -0,0 +1,772 @@
+package goskills
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/smallnest/goskills/tool"
+	"gopkg.in/yaml.v3"
+)
+
+// ScriptSpecSuffix ends the name of a sidecar spec, which sits next to the
+// script it describes and replaces the script's extension:
+// scripts/convert.py is described by scripts/convert.tool.yaml.
+const ScriptSpecSuffix = ".tool.yaml"
+
+// Sources of a ScriptSpec.
+const (
+	SpecSidecar = "sidecar" // A ScriptSpecSuffix file next to the script
+	SpecHeader  = "header"  // The script's leading comment block or docstring
+	SpecHelp    = "help"    // The argparse usage printed by the script's --help
+)
+
+// Parameter types of a ScriptParam, named as in JSON Schema.
+const (
+	ParamString  = "string"
+	ParamInteger = "integer"
+	ParamNumber  = "number"
+	ParamBoolean = "boolean"
+	ParamArray   = "array"
+)
+
+// ScriptSpec is the command-line interface a script declares. It is turned
+// into the JSON Schema of the script's tool, and maps the named parameters of
+// a tool call back to command-line arguments.
+type ScriptSpec struct {
+	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
+	Parameters  []ScriptParam `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Source      string        `yaml:"-" json:"source"` // SpecSidecar, SpecHeader or SpecHelp
+}
+
+// ScriptParam is a named parameter of a script. Parameters with a Flag are
+// passed as options, before the positional parameters, which are passed in
+// the order they are declared.
+type ScriptParam struct {
+	Name        string      `yaml:"name" json:"name"`
+	Type        string      `yaml:"type,omitempty" json:"type,omitempty"`   // ParamString when empty
+	Items       string      `yaml:"items,omitempty" json:"items,omitempty"` // Element type of an array; ParamString when empty
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`
+	Enum        []string    `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Default     interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+	Flag        string      `yaml:"flag,omitempty" json:"flag,omitempty"` // Option the value follows, such as "--output"; empty for a positional argument
+}
+
+// paramNamePattern matches the parameter names accepted in tool schemas.
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// paramType returns the type of the parameter, defaulting to ParamString.
+func (p ScriptParam) paramType() string {
+	if p.Type == "" {
+		return ParamString
+	}
+	return p.Type
+}
+
+// itemType returns the element type of an array parameter, defaulting to ParamString.
+func (p ScriptParam) itemType() string {
+	if p.Items == "" {
+		return ParamString
+	}
+	return p.Items
+}
+
+// check reports the first problem with the spec's parameters.
+func (s *ScriptSpec) check() error {
+	seen := make(map[string]bool)
+	for _, p := range s.Parameters {
+		if !paramNamePattern.MatchString(p.Name) {
+			return fmt.Errorf("invalid parameter name '%s'", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("parameter '%s' is declared more than once", p.Name)
+		}
+		seen[p.Name] = true
+		switch p.paramType() {
+		case ParamString, ParamInteger, ParamNumber, ParamBoolean:
+		case ParamArray:
+			switch p.itemType() {
+			case ParamString, ParamInteger, ParamNumber, ParamBoolean:
+			default:
+				return fmt.Errorf("parameter '%s' has unknown item type '%s'", p.Name, p.Items)
+			}
+		default:
+			return fmt.Errorf("parameter '%s' has unknown type '%s'", p.Name, p.Type)
+		}
+		if p.Flag != "" && !strings.HasPrefix(p.Flag, "-") {
+			return fmt.Errorf("flag '%s' of parameter '%s' must start with '-'", p.Flag, p.Name)
+		}
+	}
+	return nil
+}
+
+// ScriptSpec returns the interface declared by a script of the skill, given
+// its path relative to the skill root. It is read from a sidecar spec if
+// there is one, or else from the script's header. With WithHelpCapture, a
+// Python script that uses argparse and declares neither is run with --help
+// and its usage is parsed. It returns nil if the script declares nothing.
+//
+// A header is the comment block or docstring at the top of the script. Its
+// first paragraph, or an @description tag, describes the script, and each
+// parameter is declared with an @param tag in JSDoc style:
+//
+//	# Convert a document to PDF.
+//	#
+//	# @param {string} input Document to convert
+//	# @param {string} [--output] Where to write the PDF
+//	# @param {a4|letter} [--paper=a4] Paper size
+//	# @param {boolean} [--verbose] Print progress
+//	# @param {string[]} [pages] Pages to include
+//
+// Brackets make a parameter optional, a leading '-' makes it a flag, and
+// "=value" sets its default. Types are string, integer, number, boolean, an
+// array of one of them such as string[], or a list of allowed values
+// separated by '|'.
+func (p *SkillPackage) ScriptSpec(script string, opts ...ToolOption) (*ScriptSpec, error) {
+	o := newToolOptions(opts)
+	rel := filepath.ToSlash(script)
+	sidecar := strings.TrimSuffix(rel, path.Ext(rel)) + ScriptSpecSuffix
+	if data, err := p.ReadResource(sidecar); err == nil {
+		spec := &ScriptSpec{}
+		if err := yaml.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("%s: %w", sidecar, err)
+		}
+		spec.Source = SpecSidecar
+		if err := spec.check(); err != nil {
+			return nil, fmt.Errorf("%s: %w", sidecar, err)
+		}
+		return spec, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	data, err := p.ReadResource(rel)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := parseScriptHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rel, err)
+	}
+	if spec != nil && len(spec.Parameters) > 0 {
+		return spec, nil
+	}
+
+	if o.helpTimeout > 0 && p.native && argparsePattern.Match(data) {
+		if help, err := p.captureHelp(rel, data, o); err == nil {
+			if helpSpec := parseArgparseHelp(help); helpSpec != nil {
+				if helpSpec.Description == "" && spec != nil {
+					helpSpec.Description = spec.Description
+				}
+				return helpSpec, nil
+			}
+		}
+	}
+	return spec, nil
+}
+
+// Schema returns the JSON Schema of the parameters of the script's tool. A
+// spec without parameters, like a nil one, takes a free-form list of
+// arguments.
+func (s *ScriptSpec) Schema() map[string]interface{} {
+	if s == nil || len(s.Parameters) == 0 {
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"args": map[string]interface{}{
+					"type":        "array",
+					"description": "Arguments to pass to the script.",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+		}
+	}
+
+	properties := make(map[string]interface{}, len(s.Parameters))
+	required := []string{}
+	for _, p := range s.Parameters {
+		prop := map[string]interface{}{"type": p.paramType()}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		enumType := p.paramType()
+		if enumType == ParamArray {
+			items := map[string]interface{}{"type": p.itemType()}
+			enumType = p.itemType()
+			if len(p.Enum) > 0 {
+				items["enum"] = enumValues(p.Enum, enumType)
+			}
+			prop["items"] = items
+		} else if len(p.Enum) > 0 {
+			prop["enum"] = enumValues(p.Enum, enumType)
+		}
+		if p.Default != nil {
+			prop["default"] = p.Default
+		}
+		properties[p.Name] = prop
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// enumValues converts allowed values to the JSON type of the parameter, keeping
+// those that do not convert as strings.
+func enumValues(values []string, typ string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+		switch typ {
+		case ParamInteger:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				out[i] = n
+			}
+		case ParamNumber:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				out[i] = f
+			}
+		case ParamBoolean:
+			if b, err := strconv.ParseBool(v); err == nil {
+				out[i] = b
+			}
+		}
+	}
+	return out
+}
+
+// Args maps the JSON arguments of a call to the script's tool to its command
+// line: flags in declaration order, then positional parameters. Parameters
+// are checked against their types, allowed values and whether they are
+// required; a boolean flag is passed only when true. A spec without
+// parameters, like a nil one, passes the 'args' list through.
+func (s *ScriptSpec) Args(arguments string) ([]string, error) {
+	if s == nil || len(s.Parameters) == 0 {
+		var params struct {
+			Args []string `json:"args"`
+		}
+		if strings.TrimSpace(arguments) != "" {
+			if err := json.Unmarshal([]byte(arguments), &params); err != nil {
+				return nil, fmt.Errorf("invalid script arguments: %w", err)
+			}
+		}
+		return params.Args, nil
+	}
+
+	values := make(map[string]interface{})
+	if strings.TrimSpace(arguments) != "" {
+		dec := json.NewDecoder(strings.NewReader(arguments))
+		dec.UseNumber()
+		if err := dec.Decode(&values); err != nil {
+			return nil, fmt.Errorf("invalid script arguments: %w", err)
+		}
+	}
+
+	var flags, positional []string
+	skipped := "" // Last optional positional parameter left out
+	for _, p := range s.Parameters {
+		raw, ok := values[p.Name]
+		delete(values, p.Name)
+		if !ok || raw == nil {
+			if p.Required {
+				return nil, fmt.Errorf("missing required parameter '%s'", p.Name)
+			}
+			if p.Flag == "" {
+				skipped = p.Name
+			}
+			continue
+		}
+		args, err := p.format(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.Flag == "":
+			if skipped != "" {
+				return nil, fmt.Errorf("parameter '%s' is given without '%s', which comes before it", p.Name, skipped)
+			}
+			positional = append(positional, args...)
+		case p.paramType() == ParamBoolean:
+			if args[0] == "true" {
+				flags = append(flags, p.Flag)
+			}
+		default:
+			for _, a := range args {
+				flags = append(flags, p.Flag, a)
+			}
+		}
+	}
+	if len(values) > 0 {
+		unknown := make([]string, 0, len(values))
+		for name := range values {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown parameter '%s'", unknown[0])
+	}
+	return append(flags, positional...), nil
+}
+
+// format converts a decoded JSON value of the parameter to command-line arguments.
+func (p ScriptParam) format(v interface{}) ([]string, error) {
+	if p.paramType() != ParamArray {
+		arg, err := formatScalar(p.Name, p.paramType(), v, p.Enum)
+		if err != nil {
+			return nil, err
+		}
+		return []string{arg}, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		list = []interface{}{v}
+	}
+	args := make([]string, 0, len(list))
+	for _, item := range list {
+		arg, err := formatScalar(p.Name, p.itemType(), item, p.Enum)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// formatScalar converts a decoded JSON value to an argument of type typ. Strings
+// holding a value of the right type are accepted too, since models often quote
+// numbers and booleans.
+func formatScalar(name, typ string, v interface{}, enum []string) (string, error) {
+	arg, ok := "", false
+	switch typ {
+	case ParamString:
+		switch v := v.(type) {
+		case string:
+			arg, ok = v, true
+		case json.Number, bool:
+			arg, ok = fmt.Sprint(v), true
+		}
+	case ParamInteger:
+		if _, err := strconv.ParseInt(fmt.Sprint(v), 10, 64); err == nil && !isBool(v) {
+			arg, ok = fmt.Sprint(v), true
+		}
+	case ParamNumber:
+		if _, err := strconv.ParseFloat(fmt.Sprint(v), 64); err == nil && !isBool(v) {
+			arg, ok = fmt.Sprint(v), true
+		}
+	case ParamBoolean:
+		if b, err := strconv.ParseBool(fmt.Sprint(v)); err == nil {
+			arg, ok = strconv.FormatBool(b), true
+		}
+	}
+	if !ok {
+		return "", fmt.Errorf("parameter '%s' must be of type %s, got %v", name, typ, v)
+	}
+	if len(enum) > 0 && indexOf(enum, arg) < 0 {
+		return "", fmt.Errorf("parameter '%s' must be one of %s, got '%s'", name, strings.Join(enum, ", "), arg)
+	}
+	return arg, nil
+}
+
+func isBool(v interface{}) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+// paramTypeNames maps the type names accepted in script headers to parameter types.
+var paramTypeNames = map[string]string{
+	"string": ParamString, "str": ParamString, "path": ParamString, "file": ParamString,
+	"integer": ParamInteger, "int": ParamInteger,
+	"number": ParamNumber, "float": ParamNumber,
+	"boolean": ParamBoolean, "bool": ParamBoolean, "flag": ParamBoolean,
+}
+
+// headerTagPattern matches a tag line of a script header.
+var headerTagPattern = regexp.MustCompile(`^@(\w+)\s*(.*)$`)
+
+// parseScriptHeader reads the leading comment block or docstring of a script.
+// It returns nil if the script has none.
+func parseScriptHeader(data []byte) (*ScriptSpec, error) {
+	lines := scriptHeaderLines(string(data))
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	spec := &ScriptSpec{Source: SpecHeader}
+	var prose []string
+	tagged := false
+	for i := 0; i < len(lines); i++ {
+		m := headerTagPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			if !tagged {
+				prose = append(prose, lines[i])
+			}
+			continue
+		}
+		tagged = true
+		text := m[2]
+		// A tag continues on following lines up to a blank line or the next tag.
+		for i+1 < len(lines) && lines[i+1] != "" && !headerTagPattern.MatchString(lines[i+1]) {
+			i++
+			text += " " + lines[i]
+		}
+		switch m[1] {
+		case "description":
+			spec.Description = text
+		case "param":
+			param, err := parseHeaderParam(text)
+			if err != nil {
+				return nil, err
+			}
+			spec.Parameters = append(spec.Parameters, param)
+		}
+	}
+	if spec.Description == "" {
+		spec.Description = firstParagraph(prose)
+	}
+	if spec.Description == "" && len(spec.Parameters) == 0 {
+		return nil, nil
+	}
+	if err := spec.check(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// scriptHeaderLines returns the trimmed text of the comment lines and
+// docstring at the start of a script, after its shebang.
+func scriptHeaderLines(src string) []string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var header []string
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case i == 0 && strings.HasPrefix(line, "#!"):
+		case strings.HasPrefix(line, "# -*-"), strings.HasPrefix(line, "# vim:"):
+		case line == "":
+			header = append(header, "")
+		case strings.HasPrefix(line, "#"):
+			header = append(header, strings.TrimSpace(strings.TrimLeft(line, "#")))
+		case strings.HasPrefix(line, "//"):
+			header = append(header, strings.TrimSpace(strings.TrimLeft(line, "/")))
+		case strings.HasPrefix(line, `"""`), strings.HasPrefix(line, "'''"):
+			quote := line[:3]
+			rest := line[3:]
+			for {
+				if end := strings.Index(rest, quote); end >= 0 {
+					header = append(header, strings.TrimSpace(rest[:end]))
+					break
+				}
+				header = append(header, strings.TrimSpace(rest))
+				i++
+				if i == len(lines) {
+					break
+				}
+				rest = lines[i]
+			}
+		default:
+			return header
+		}
+	}
+	return header
+}
+
+// firstParagraph joins the lines of the first paragraph that is not a usage synopsis.
+func firstParagraph(lines []string) string {
+	var para []string
+	for _, line := range lines {
+		if line != "" {
+			para = append(para, line)
+			continue
+		}
+		if len(para) > 0 && !isUsage(para[0]) {
+			break
+		}
+		para = nil
+	}
+	if len(para) == 0 || isUsage(para[0]) {
+		return ""
+	}
+	return strings.Join(para, " ")
+}
+
+func isUsage(line string) bool {
+	return strings.HasPrefix(strings.ToLower(line), "usage")
+}
+
+// parseHeaderParam parses the text of an @param tag: "{type} name description".
+func parseHeaderParam(text string) (ScriptParam, error) {
+	var p ScriptParam
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "{") {
+		end := strings.Index(text, "}")
+		if end < 0 {
+			return p, fmt.Errorf("@param %s: unterminated type", text)
+		}
+		if err := p.setHeaderType(strings.TrimSpace(text[1:end])); err != nil {
+			return p, fmt.Errorf("@param %s: %w", text, err)
+		}
+		text = strings.TrimSpace(text[end+1:])
+	}
+
+	name, desc, _ := strings.Cut(text, " ")
+	p.Description = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(desc), "- "))
+	p.Required = true
+	if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+		p.Required = false
+		name = name[1 : len(name)-1]
+		if n, def, ok := strings.Cut(name, "="); ok {
+			name = n
+			p.Default = headerDefault(def, p.paramType())
+		}
+	}
+	if strings.HasPrefix(name, "-") {
+		p.Flag = name
+		name = strings.TrimLeft(name, "-")
+	}
+	p.Name = strings.ReplaceAll(name, "-", "_")
+	if p.Name == "" {
+		return p, fmt.Errorf("@param %s: missing parameter name", text)
+	}
+	return p, nil
+}
+
+// setHeaderType sets the type of a parameter from the type of an @param tag.
+func (p *ScriptParam) setHeaderType(typ string) error {
+	if strings.Contains(typ, "|") {
+		for _, v := range strings.Split(typ, "|") {
+			p.Enum = append(p.Enum, strings.Trim(strings.TrimSpace(v), `"'`))
+		}
+		return nil
+	}
+	elem, isArray := strings.CutSuffix(typ, "[]")
+	t, ok := paramTypeNames[strings.ToLower(elem)]
+	if !ok {
+		return fmt.Errorf("unknown type '%s'", typ)
+	}
+	if isArray {
+		p.Type, p.Items = ParamArray, t
+	} else {
+		p.Type = t
+	}
+	return nil
+}
+
+// headerDefault converts the default value of an @param tag to the parameter's type.
+func headerDefault(def, typ string) interface{} {
+	switch typ {
+	case ParamInteger:
+		if n, err := strconv.ParseInt(def, 10, 64); err == nil {
+			return n
+		}
+	case ParamNumber:
+		if f, err := strconv.ParseFloat(def, 64); err == nil {
+			return f
+		}
+	case ParamBoolean:
+		if b, err := strconv.ParseBool(def); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// argparsePattern matches Python source that imports argparse.
+var argparsePattern = regexp.MustCompile(`(?m)^\s*(?:import\s+argparse|from\s+argparse\s+import)`)
+
+// captureHelp runs the script at rel, whose content is data, with --help
+// and the interpreter o resolves for it, and returns what it printed. The
+// script runs in its own directory.
+func (p *SkillPackage) captureHelp(rel string, data []byte, o *toolOptions) (string, error) {
+	info, err := p.statResource(rel)
+	if err != nil {
+		return "", err
+	}
+	in, ok := o.interpreters.Resolve(rel, data, info.Mode()&0o111 != 0)
+	if !ok {
+		return "", fmt.Errorf("no interpreter runs %s", rel)
+	}
+	script, err := filepath.Abs(filepath.Join(p.Path, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.helpTimeout)
+	defer cancel()
+	cmd := tool.ScriptCommand(ctx, in, script, []string{"--help"})
+	cmd.Dir = filepath.Dir(script)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// parseArgparseHelp reads the parameters of a script from the help text that
+// argparse prints. It returns nil if the text is not argparse help, or names
+// a positional argument only by its choices, since the order of positional
+// parameters must be complete.
+func parseArgparseHelp(help string) *ScriptSpec {
+	lines := strings.Split(strings.ReplaceAll(help, "\r\n", "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "usage:") {
+		return nil
+	}
+
+	// The usage synopsis may wrap onto indented lines.
+	i := 1
+	usage := strings.TrimPrefix(lines[0], "usage:")
+	for ; i < len(lines) && strings.HasPrefix(lines[i], " ") && strings.TrimSpace(lines[i]) != ""; i++ {
+		usage += " " + strings.TrimSpace(lines[i])
+	}
+	u := parseUsage(usage)
+
+	spec := &ScriptSpec{Source: SpecHelp}
+	inSections := false
+	var desc []string
+	var current *ScriptParam
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case trimmed == "":
+			continue
+		case indent == 0:
+			// A section title such as "options:", or description or epilog text.
+			current = nil
+			if strings.HasSuffix(trimmed, ":") {
+				inSections = true
+			} else if !inSections {
+				desc = append(desc, trimmed)
+			}
+			continue
+		case indent > 2:
+			// Help text wrapped below its argument.
+			if current != nil {
+				current.Description = strings.TrimSpace(current.Description + " " + trimmed)
+			}
+			continue
+		}
+
+		invocation, text := trimmed, ""
+		if idx := strings.Index(trimmed, "  "); idx >= 0 {
+			invocation, text = trimmed[:idx], strings.TrimSpace(trimmed[idx:])
+		}
+		param, ok := u.param(invocation)
+		if !ok {
+			if !strings.HasPrefix(invocation, "-") {
+				return nil
+			}
+			current = nil
+			continue
+		}
+		param.Description = text
+		spec.Parameters = append(spec.Parameters, param)
+		current = &spec.Parameters[len(spec.Parameters)-1]
+	}
+	spec.Description = strings.Join(desc, " ")
+	if len(spec.Parameters) == 0 || spec.check() != nil {
+		return nil
+	}
+	return spec
+}
+
+// argparseUsage is what an argparse usage synopsis tells about its arguments.
+type argparseUsage struct {
+	requiredFlags      map[string]bool // Options shown outside brackets
+	optionalPositional map[string]bool // Positional arguments shown in brackets
+	repeated           map[string]bool // Arguments followed by "..."
+}
+
+// parseUsage analyzes a usage synopsis such as
+// "prog [-h] -o OUT [--page PAGE [PAGE ...]] input [extra ...]".
+func parseUsage(usage string) argparseUsage {
+	u := argparseUsage{
+		requiredFlags:      make(map[string]bool),
+		optionalPositional: make(map[string]bool),
+		repeated:           make(map[string]bool),
+	}
+	depth := 0
+	prev := ""
+	for _, tok := range strings.Fields(usage) {
+		word := strings.Trim(tok, "[]")
+		switch {
+		case word == "...":
+			u.repeated[prev] = true
+		case depth == 0 && strings.HasPrefix(tok, "-"):
+			u.requiredFlags[word] = true
+		case depth == 0 && strings.HasPrefix(tok, "[") && !strings.HasPrefix(word, "-"):
+			u.optionalPositional[word] = true
+		}
+		if word != "..." {
+			prev = word
+		}
+		depth += strings.Count(tok, "[") - strings.Count(tok, "]")
+	}
+	return u
+}
+
+// param converts the invocation column of an argparse help entry, such as
+// "input", "--format {xml,json}" or "-o OUTPUT, --output OUTPUT", into a
+// parameter. It returns false for the help option and for positional
+// arguments shown only by their choices.
+func (u argparseUsage) param(invocation string) (ScriptParam, bool) {
+	var p ScriptParam
+	if !strings.HasPrefix(invocation, "-") {
+		if strings.HasPrefix(invocation, "{") {
+			return p, false
+		}
+		p.Name = invocation
+		p.Required = !u.optionalPositional[p.Name]
+		if u.repeated[p.Name] {
+			p.Type = ParamArray
+		}
+		return p, true
+	}
+
+	metavar := ""
+	for _, alt := range strings.Split(invocation, ", ") {
+		flag, mv, _ := strings.Cut(strings.TrimSpace(alt), " ")
+		if flag == "-h" || flag == "--help" {
+			return p, false
+		}
+		if p.Flag == "" || (strings.HasPrefix(flag, "--") && !strings.HasPrefix(p.Flag, "--")) {
+			p.Flag, metavar = flag, mv
+		}
+		if u.requiredFlags[flag] {
+			p.Required = true
+		}
+	}
+	p.Name = strings.ReplaceAll(strings.TrimLeft(p.Flag, "-"), "-", "_")
+	switch {
+	case metavar == "":
+		p.Type = ParamBoolean
+	case strings.Contains(metavar, "..."):
+		p.Type = ParamArray
+	}
+	if strings.HasPrefix(metavar, "{") {
+		if end := strings.Index(metavar, "}"); end > 0 {
+			p.Enum = strings.Split(metavar[1:end], ",")
+		}
+	}
+	return p, true
+}
@@ -0,0 +1,237 @@
+package goskills
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptSpecSkill writes a skill with the given scripts and returns its package.
+func scriptSpecSkill(t *testing.T, files map[string]string) *SkillPackage {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "converter")
+	writeSkill(t, dir, "name: converter\ndescription: Converts documents.")
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o755))
+	}
+	pkg, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestScriptSpec_Header(t *testing.T) {
+	pkg := scriptSpecSkill(t, map[string]string{
+		"scripts/convert.py": `#!/usr/bin/env python3
+"""Convert a document to PDF.
+
+Usage: convert.py [--paper SIZE] input [pages...]
+
+@param {string} input Document to convert
+@param {a4|letter} [--paper=a4] Paper size
+@param {boolean} [--verbose] Print progress,
+    one line per page
+@param {integer[]} [pages] Pages to include
+"""
+import sys
+`,
+		"scripts/plain.sh": "#!/bin/bash\n# Prints the date.\ndate\n",
+		"scripts/bare.sh":  "date\n",
+	})
+
+	spec, err := pkg.ScriptSpec("scripts/convert.py")
+	require.NoError(t, err)
+	assert.Equal(t, &ScriptSpec{
+		Description: "Convert a document to PDF.",
+		Source:      SpecHeader,
+		Parameters: []ScriptParam{
+			{Name: "input", Type: ParamString, Description: "Document to convert", Required: true},
+			{Name: "paper", Enum: []string{"a4", "letter"}, Default: "a4", Flag: "--paper", Description: "Paper size"},
+			{Name: "verbose", Type: ParamBoolean, Flag: "--verbose", Description: "Print progress, one line per page"},
+			{Name: "pages", Type: ParamArray, Items: ParamInteger, Description: "Pages to include"},
+		},
+	}, spec)
+
+	spec, err = pkg.ScriptSpec("scripts/plain.sh")
+	require.NoError(t, err)
+	assert.Equal(t, &ScriptSpec{Description: "Prints the date.", Source: SpecHeader}, spec)
+
+	spec, err = pkg.ScriptSpec("scripts/bare.sh")
+	require.NoError(t, err)
+	assert.Nil(t, spec)
+}
+
+func TestScriptSpec_Sidecar(t *testing.T) {
+	pkg := scriptSpecSkill(t, map[string]string{
+		"scripts/convert.py": "# @param {string} ignored Overridden by the sidecar\n",
+		"scripts/convert.tool.yaml": `description: Convert a document to PDF.
+parameters:
+  - name: input
+    description: Document to convert
+    required: true
+  - name: dpi
+    type: integer
+    flag: --dpi
+    enum: ["150", "300"]
+`,
+		"scripts/broken.sh":        "echo broken\n",
+		"scripts/broken.tool.yaml": "parameters:\n  - name: x\n    type: date\n",
+	})
+	assert.Equal(t, []string{"scripts/broken.sh", "scripts/convert.py"}, pkg.Resources.Scripts, "sidecars are not scripts")
+
+	spec, err := pkg.ScriptSpec("scripts/convert.py")
+	require.NoError(t, err)
+	assert.Equal(t, SpecSidecar, spec.Source)
+	require.Len(t, spec.Parameters, 2)
+
+	schema := spec.Schema()
+	assert.Equal(t, []string{"input"}, schema["required"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	dpi := schema["properties"].(map[string]interface{})["dpi"].(map[string]interface{})
+	assert.Equal(t, "integer", dpi["type"])
+	assert.Equal(t, []interface{}{int64(150), int64(300)}, dpi["enum"])
+
+	_, err = pkg.ScriptSpec("scripts/broken.sh")
+	assert.ErrorContains(t, err, "unknown type 'date'")
+	var rules []string
+	for _, d := range Validate(pkg) {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{RuleScriptSpec}, rules)
+
+	tools, scripts := GenerateTools(*pkg)
+	require.Contains(t, scripts, "run_scripts_convert_py")
+	assert.Equal(t, spec, scripts["run_scripts_convert_py"].Spec)
+	assert.Nil(t, scripts["run_scripts_broken_sh"].Spec, "a broken spec falls back to untyped arguments")
+	for _, tool := range tools {
+		if tool.Function.Name == "run_scripts_convert_py" {
+			assert.Equal(t, "Convert a document to PDF (script 'scripts/convert.py')", tool.Function.Description)
+			assert.Equal(t, schema, tool.Function.Parameters)
+		}
+	}
+}
+
+func TestScriptSpec_Args(t *testing.T) {
+	spec := &ScriptSpec{Parameters: []ScriptParam{
+		{Name: "input", Required: true},
+		{Name: "output"},
+		{Name: "paper", Flag: "--paper", Enum: []string{"a4", "letter"}},
+		{Name: "verbose", Type: ParamBoolean, Flag: "-v"},
+		{Name: "dpi", Type: ParamInteger, Flag: "--dpi"},
+		{Name: "tag", Type: ParamArray, Flag: "--tag"},
+	}}
+
+	tests := []struct {
+		arguments string
+		want      []string
+		err       string
+	}{
+		{`{"input": "a.docx"}`, []string{"a.docx"}, ""},
+		{`{"input": "a.docx", "output": "a.pdf", "paper": "letter", "verbose": true, "dpi": 300, "tag": ["x", "y"]}`,
+			[]string{"--paper", "letter", "-v", "--dpi", "300", "--tag", "x", "--tag", "y", "a.docx", "a.pdf"}, ""},
+		{`{"input": "a.docx", "verbose": false, "dpi": "150"}`, []string{"--dpi", "150", "a.docx"}, ""},
+		{`{}`, nil, "missing required parameter 'input'"},
+		{`{"input": "a", "paper": "a3"}`, nil, "must be one of a4, letter"},
+		{`{"input": "a", "dpi": 1.5}`, nil, "must be of type integer"},
+		{`{"input": "a", "color": true}`, nil, "unknown parameter 'color'"},
+		{`not json`, nil, "invalid script arguments"},
+	}
+	for _, tt := range tests {
+		got, err := spec.Args(tt.arguments)
+		if tt.err != "" {
+			assert.ErrorContains(t, err, tt.err, tt.arguments)
+			continue
+		}
+		require.NoError(t, err, tt.arguments)
+		assert.Equal(t, tt.want, got, tt.arguments)
+	}
+
+	var untyped *ScriptSpec
+	args, err := untyped.Args(`{"args": ["-x", "1"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-x", "1"}, args)
+}
+
+func TestParseArgparseHelp(t *testing.T) {
+	help := `usage: unpack.py [-h] [--format {xml,json}] -o OUTPUT_NAME [--verbose]
+                 [--page PAGE [PAGE ...]]
+                 input_file [output_dir]
+
+Unpack an Office file into formatted XML.
+
+positional arguments:
+  input_file            Office file to unpack
+  output_dir            Directory to write to
+
+options:
+  -h, --help            show this help message and exit
+  --format {xml,json}   Output format
+  -o OUTPUT_NAME, --output-name OUTPUT_NAME
+                        Name of the output, which is long enough to
+                        wrap
+  --verbose             Print progress
+  --page PAGE [PAGE ...]
+                        Pages
+`
+	spec := parseArgparseHelp(help)
+	require.NotNil(t, spec)
+	assert.Equal(t, "Unpack an Office file into formatted XML.", spec.Description)
+	assert.Equal(t, []ScriptParam{
+		{Name: "input_file", Description: "Office file to unpack", Required: true},
+		{Name: "output_dir", Description: "Directory to write to"},
+		{Name: "format", Flag: "--format", Enum: []string{"xml", "json"}, Description: "Output format"},
+		{Name: "output_name", Flag: "--output-name", Required: true, Description: "Name of the output, which is long enough to wrap"},
+		{Name: "verbose", Type: ParamBoolean, Flag: "--verbose", Description: "Print progress"},
+		{Name: "page", Type: ParamArray, Flag: "--page", Description: "Pages"},
+	}, spec.Parameters)
+
+	assert.Nil(t, parseArgparseHelp("Prints the date.\n"))
+	assert.Nil(t, parseArgparseHelp("usage: x.py [-h] {a,b}\n\npositional arguments:\n  {a,b}  Mode\n"), "unnamed positionals make the order unknowable")
+}
+
+func TestScriptSpec_HelpCapture(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	pkg := scriptSpecSkill(t, map[string]string{
+		"scripts/greet.py": `import argparse
+parser = argparse.ArgumentParser(description="Greets someone.")
+parser.add_argument("name", help="Who to greet")
+parser.add_argument("--shout", action="store_true", help="Use capitals")
+parser.parse_args()
+`,
+	})
+
+	spec, err := pkg.ScriptSpec("scripts/greet.py")
+	require.NoError(t, err)
+	assert.Nil(t, spec, "scripts are not run without WithHelpCapture")
+
+	spec, err = pkg.ScriptSpec("scripts/greet.py", WithHelpCapture(10*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, spec)
+	assert.Equal(t, SpecHelp, spec.Source)
+	assert.Equal(t, "Greets someone.", spec.Description)
+	assert.Equal(t, []ScriptParam{
+		{Name: "name", Description: "Who to greet", Required: true},
+		{Name: "shout", Type: ParamBoolean, Flag: "--shout", Description: "Use capitals"},
+	}, spec.Parameters)
+
+	// A relative skill path and a script picked by its shebang alone.
+	wave := "#!/usr/bin/env python3\nimport argparse\nparser = argparse.ArgumentParser()\nparser.add_argument(\"--hand\", help=\"Which hand\")\nparser.parse_args()\n"
+	require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, "scripts", "wave"), []byte(wave), 0o755))
+	t.Chdir(filepath.Dir(pkg.Path))
+	rel, err := ParseSkillPackage(filepath.Base(pkg.Path))
+	require.NoError(t, err)
+	for _, script := range []string{"scripts/greet.py", "scripts/wave"} {
+		spec, err = rel.ScriptSpec(script, WithHelpCapture(10*time.Second))
+		require.NoError(t, err)
+		require.NotNil(t, spec, script)
+		assert.Equal(t, SpecHelp, spec.Source, script)
+	}
+}
@@ -0,0 +1,360 @@
+package goskills
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, as defined by semver.org.
+type Version struct {
+	Major, Minor, Patch uint64
+	Prerelease          string // Dot-separated pre-release identifiers, without the leading '-'
+	Build               string // Build metadata, without the leading '+'; ignored in comparisons
+}
+
+// ParseVersion parses a semantic version such as "1.4.2", "2.0.0-rc.1" or
+// "1.0.0+build.5". A leading 'v' is accepted.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	rest := strings.TrimPrefix(s, "v")
+	var hasBuild, hasPre bool
+	rest, v.Build, hasBuild = strings.Cut(rest, "+")
+	rest, v.Prerelease, hasPre = strings.Cut(rest, "-")
+	if (hasBuild && v.Build == "") || (hasPre && v.Prerelease == "") {
+		return Version{}, fmt.Errorf("invalid version '%s': empty pre-release or build metadata", s)
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version '%s': expected MAJOR.MINOR.PATCH", s)
+	}
+	nums := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := parseNumericIdentifier(p)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version '%s': %v", s, err)
+		}
+		*nums[i] = n
+	}
+	if err := checkIdentifiers(v.Prerelease, true); err != nil {
+		return Version{}, fmt.Errorf("invalid version '%s': pre-release %v", s, err)
+	}
+	if err := checkIdentifiers(v.Build, false); err != nil {
+		return Version{}, fmt.Errorf("invalid version '%s': build metadata %v", s, err)
+	}
+	return v, nil
+}
+
+// parseNumericIdentifier parses a version number, which has no leading zeros.
+func parseNumericIdentifier(s string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty number")
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("number '%s' has a leading zero", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a number", s)
+	}
+	return n, nil
+}
+
+// checkIdentifiers checks dot-separated pre-release or build identifiers.
+// Numeric pre-release identifiers must not have leading zeros.
+func checkIdentifiers(s string, prerelease bool) error {
+	if s == "" {
+		return nil
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return fmt.Errorf("has an empty identifier")
+		}
+		numeric := true
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				numeric = false
+			default:
+				return fmt.Errorf("identifier '%s' contains invalid character %q", id, r)
+			}
+		}
+		if prerelease && numeric && len(id) > 1 && id[0] == '0' {
+			return fmt.Errorf("identifier '%s' has a leading zero", id)
+		}
+	}
+	return nil
+}
+
+// String returns the version in canonical form, without a 'v' prefix.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 depending on whether v precedes, equals or
+// follows o. Build metadata is ignored.
+func (v Version) Compare(o Version) int {
+	for _, c := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// comparePrerelease orders pre-release strings: a release follows all of its
+// pre-releases, numeric identifiers compare as numbers and precede
+// alphanumeric ones, and a longer list follows its prefix.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		if x == y {
+			continue
+		}
+		xn, xerr := strconv.ParseUint(x, 10, 64)
+		yn, yerr := strconv.ParseUint(y, 10, 64)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case xerr == nil:
+			return -1
+		case yerr == nil:
+			return 1
+		case x < y:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// Constraint is a set of acceptable versions, such as "^1.2.0",
+// "~1.4", ">=1.0.0 <2.0.0" or "1.x || 2.x".
+type Constraint struct {
+	raw    string
+	groups [][]comparator // Alternatives separated by "||"; each holds comparators that must all match
+}
+
+// comparator is a single version comparison.
+type comparator struct {
+	op string // One of "=", "!=", ">", ">=", "<" and "<="
+	v  Version
+}
+
+func (c comparator) matches(v Version) bool {
+	n := v.Compare(c.v)
+	switch c.op {
+	case "!=":
+		return n != 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	}
+	return n == 0
+}
+
+// ParseConstraint parses a version constraint. A constraint is one or more
+// alternatives separated by "||"; an alternative is a list of terms,
+// separated by spaces or commas, that must all hold. A term is one of:
+//
+//   - "*", "x" or an empty string: any version
+//   - "1.2.3" or "=1.2.3": exactly that version
+//   - "1.2", "1.2.x", "1" or "1.x": any version with that prefix
+//   - ">1.2.3", ">=1.2", "<2", "<=1.2.3" or "!=1.2.3": a comparison, where
+//     missing parts count as zero
+//   - "^1.2.3": compatible versions, up to the next major version, or the
+//     next minor version for 0.x releases
+//   - "~1.2.3": versions up to the next minor version
+//
+// Pre-release versions only satisfy a constraint that names a pre-release of
+// the same major, minor and patch version.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	for _, alt := range strings.Split(s, "||") {
+		var group []comparator
+		for _, term := range strings.FieldsFunc(alt, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' }) {
+			cmps, err := parseConstraintTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint '%s': %v", c.raw, err)
+			}
+			group = append(group, cmps...)
+		}
+		if strings.TrimSpace(alt) == "" && strings.Contains(s, "||") {
+			return nil, fmt.Errorf("invalid constraint '%s': empty alternative", c.raw)
+		}
+		c.groups = append(c.groups, group)
+	}
+	return c, nil
+}
+
+// parseConstraintTerm turns one term of a constraint into comparators.
+func parseConstraintTerm(term string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op, term = prefix, term[len(prefix):]
+			break
+		}
+	}
+	v, parts, err := parsePartialVersion(term)
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		if op != "" && op != "=" && op != ">=" {
+			return nil, fmt.Errorf("'%s' needs a version", op)
+		}
+		return nil, nil
+	}
+
+	// next returns the first version past the partial version at the given part.
+	next := func(part int) Version {
+		switch part {
+		case 1:
+			return Version{Major: v.Major + 1, Prerelease: "0"}
+		case 2:
+			return Version{Major: v.Major, Minor: v.Minor + 1, Prerelease: "0"}
+		}
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, Prerelease: "0"}
+	}
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			return []comparator{{"=", v}}, nil
+		}
+		return []comparator{{">=", v}, {"<", next(parts)}}, nil
+	case "^":
+		switch {
+		case v.Major > 0 || parts == 1:
+			return []comparator{{">=", v}, {"<", next(1)}}, nil
+		case v.Minor > 0 || parts == 2:
+			return []comparator{{">=", v}, {"<", next(2)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", next(3)}}, nil
+	case "~":
+		if parts == 1 {
+			return []comparator{{">=", v}, {"<", next(1)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", next(2)}}, nil
+	case ">":
+		if parts < 3 {
+			return []comparator{{">=", next(parts)}}, nil
+		}
+	case "<=":
+		if parts < 3 {
+			return []comparator{{"<", next(parts)}}, nil
+		}
+	}
+	return []comparator{{op, v}}, nil
+}
+
+// parsePartialVersion parses a version that may omit its minor and patch
+// parts or give them as 'x', '*' or 'X'. It returns the number of parts given.
+func parsePartialVersion(s string) (Version, int, error) {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return Version{}, 0, nil
+	}
+	core, pre, hasPre := strings.Cut(s, "-")
+	core, _, _ = strings.Cut(core, "+")
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("'%s' has too many parts", s)
+	}
+	var v Version
+	nums := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	given := 0
+	for i, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			continue
+		}
+		if given < i {
+			return Version{}, 0, fmt.Errorf("'%s' has a number after a wildcard", s)
+		}
+		n, err := parseNumericIdentifier(p)
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("'%s': %v", s, err)
+		}
+		*nums[i] = n
+		given++
+	}
+	if hasPre {
+		if pre == "" || given < 3 {
+			return Version{}, 0, fmt.Errorf("'%s' has a pre-release but no patch version", s)
+		}
+		if err := checkIdentifiers(pre, true); err != nil {
+			return Version{}, 0, fmt.Errorf("'%s': pre-release %v", s, err)
+		}
+		v.Prerelease = pre
+	}
+	return v, given, nil
+}
+
+// Check reports whether v satisfies the constraint.
+func (c *Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		if groupMatches(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupMatches reports whether v satisfies every comparator of group.
+func groupMatches(group []comparator, v Version) bool {
+	for _, cmp := range group {
+		if !cmp.matches(v) {
+			return false
+		}
+	}
+	if v.Prerelease == "" {
+		return true
+	}
+	for _, cmp := range group {
+		if cmp.v.Prerelease != "" && cmp.v.Prerelease != "0" && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as it was written.
+func (c *Constraint) String() string {
+	return c.raw
+}
@@ -0,0 +1,79 @@
+package goskills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("v1.4.2-rc.1+build.7")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 4, Patch: 2, Prerelease: "rc.1", Build: "build.7"}, v)
+	assert.Equal(t, "1.4.2-rc.1+build.7", v.String())
+
+	for _, bad := range []string{"", "1.2", "1.2.3.4", "01.2.3", "1.2.x", "1.2.3-", "1.2.3-01", "1.2.3-rc..1", "1.2.3+b_1"} {
+		_, err := ParseVersion(bad)
+		assert.Error(t, err, bad)
+	}
+
+	// Precedence example from semver.org.
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := mustVersion(t, ordered[i-1]), mustVersion(t, ordered[i])
+		assert.Equal(t, -1, a.Compare(b), "%s < %s", a, b)
+		assert.Equal(t, 1, b.Compare(a), "%s > %s", b, a)
+	}
+	assert.Equal(t, 0, mustVersion(t, "1.0.0+a").Compare(mustVersion(t, "1.0.0+b")))
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		reject     []string
+	}{
+		{"", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{"*", []string{"1.2.3"}, nil},
+		{"1.2.3", []string{"1.2.3", "1.2.3+meta"}, []string{"1.2.4"}},
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.9"}},
+		{"1.x", []string{"1.0.0", "1.9.0"}, []string{"2.0.0", "2.0.0-rc.1"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-alpha"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.4", []string{"1.4.0", "1.4.7"}, []string{"1.5.0"}},
+		{"~1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.4.1", "1.5.0"}},
+		{">=1.0.0 <2.0.0", []string{"1.0.0", "1.99.0"}, []string{"0.9.0", "2.0.0"}},
+		{">=1.0, <2", []string{"1.5.0"}, []string{"2.0.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"!=1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"1.x || >=3.0.0", []string{"1.5.0", "3.1.0"}, []string{"2.0.0"}},
+		{">=2.0.0-beta.2", []string{"2.0.0-beta.3", "2.0.0", "2.1.0"}, []string{"2.0.0-beta.1", "2.1.0-beta.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			for _, v := range tt.match {
+				assert.True(t, c.Check(mustVersion(t, v)), "%s satisfies %q", v, tt.constraint)
+			}
+			for _, v := range tt.reject {
+				assert.False(t, c.Check(mustVersion(t, v)), "%s does not satisfy %q", v, tt.constraint)
+			}
+		})
+	}
+
+	for _, bad := range []string{">", "^", "1.2.3.4", "~a.b", "x.1", ">=1.2-rc.1", "1.02", "1.x ||"} {
+		_, err := ParseConstraint(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func mustVersion(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := ParseVersion(s)
+	require.NoError(t, err)
+	return v
+}
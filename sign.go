@@ -0,0 +1,308 @@
+package goskills
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SignatureFileName is the name of the file, next to the manifest, that holds
+// the signature of a skill. It is never part of the skill's resource inventory.
+const SignatureFileName = ".skill-manifest.sig"
+
+// publicKeyPrefix starts the text form of a public key.
+const publicKeyPrefix = "ed25519"
+
+// Errors reported by VerifySkill.
+var (
+	ErrUnsigned         = errors.New("skill is not signed")
+	ErrUntrustedKey     = errors.New("skill is signed with a key that is not trusted")
+	ErrSignatureInvalid = errors.New("signature does not match the skill's contents")
+)
+
+// Signature is the content of a skill's signature file: an ed25519
+// signature of its manifest, as encoded by PackSkill, and the ID of the key
+// that made it.
+type Signature struct {
+	KeyID     string `json:"key_id"`
+	Signature []byte `json:"signature"`
+}
+
+// TrustedKeys maps key IDs to the public keys whose signatures are accepted.
+type TrustedKeys map[string]ed25519.PublicKey
+
+// KeyID returns the ID of a public key: the first 16 hex digits of its SHA-256.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// FormatPublicKey returns the one-line text form of a public key, as used in
+// trusted keys files, followed by an optional comment.
+func FormatPublicKey(pub ed25519.PublicKey, comment string) string {
+	line := publicKeyPrefix + " " + base64.StdEncoding.EncodeToString(pub)
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// ParsePublicKey parses a key in the form written by FormatPublicKey.
+func ParsePublicKey(line string) (ed25519.PublicKey, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != publicKeyPrefix {
+		return nil, fmt.Errorf("expected '%s <base64 key>'", publicKeyPrefix)
+	}
+	key, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("key is %d bytes, expected %d", len(key), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// ParseTrustedKeys reads a trusted keys file: one public key per line in the
+// form written by FormatPublicKey. Blank lines and lines starting with '#'
+// are ignored.
+func ParseTrustedKeys(r io.Reader) (TrustedKeys, error) {
+	keys := make(TrustedKeys)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		pub, err := ParsePublicKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		keys[KeyID(pub)] = pub
+	}
+	return keys, scanner.Err()
+}
+
+// ReadTrustedKeys reads the trusted keys file at path.
+func ReadTrustedKeys(path string) (TrustedKeys, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	keys, err := ParseTrustedKeys(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// DefaultTrustedKeysFile returns the path of the user's trusted keys file,
+// ~/.config/goskills/trusted_keys on Linux.
+func DefaultTrustedKeysFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "goskills", "trusted_keys")
+}
+
+// MarshalPrivateKey encodes a private key as a PKCS #8 PEM block.
+func MarshalPrivateKey(priv ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKey decodes an ed25519 private key from a PKCS #8 PEM block.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("no PEM private key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an ed25519 key")
+	}
+	return priv, nil
+}
+
+// SignSkill signs the manifest of the skill at dir with priv and writes the
+// manifest and signature files into dir, replacing earlier ones. Signing
+// again is needed after any change to the skill's files.
+func SignSkill(dir string, priv ed25519.PrivateKey, opts ...ParseOption) (*Signature, error) {
+	pkg, err := ParseSkillPackage(dir, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m, err := BuildManifest(pkg)
+	if err != nil {
+		return nil, err
+	}
+	// VerifySkill refuses files the manifest does not cover.
+	unlisted, err := pkg.unlistedFile(m)
+	if err != nil {
+		return nil, err
+	}
+	if unlisted != "" {
+		return nil, fmt.Errorf("%s is not part of the skill and would not be signed; remove it before signing", unlisted)
+	}
+	manifest, err := marshalManifest(m)
+	if err != nil {
+		return nil, err
+	}
+
+	pub := priv.Public().(ed25519.PublicKey)
+	sig := &Signature{KeyID: KeyID(pub), Signature: ed25519.Sign(priv, manifest)}
+	data, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFileName), manifest, 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, SignatureFileName), append(data, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// VerifySkill checks that pkg carries a signature by one of the trusted keys
+// over a manifest of its current contents. It returns the signature on
+// success. Every file in the skill directory must be covered by the
+// manifest, including files matched by .skillignore. Only nested skill
+// packages and what the inventory leaves out by default are exempt: the
+// manifest, the signature and .skillignore files, caches that running the
+// scripts writes, such as __pycache__ and *.pyc, dependency trees and
+// version control metadata. The error wraps ErrUnsigned, ErrUntrustedKey or
+// ErrSignatureInvalid; in the last case it names a file that changed since
+// signing, when the signed manifest is still available.
+func VerifySkill(pkg *SkillPackage, keys TrustedKeys) (*Signature, error) {
+	data, err := pkg.ReadResource(SignatureFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUnsigned
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+	sig := &Signature{}
+	if err := json.Unmarshal(data, sig); err != nil {
+		return nil, fmt.Errorf("invalid signature file: %w", err)
+	}
+	pub, ok := keys[sig.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedKey, sig.KeyID)
+	}
+
+	m, err := BuildManifest(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+	}
+	manifest, err := marshalManifest(m)
+	if err != nil {
+		return nil, err
+	}
+	if ed25519.Verify(pub, manifest, sig.Signature) {
+		unlisted, err := pkg.unlistedFile(m)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+		}
+		if unlisted != "" {
+			return nil, fmt.Errorf("%w: %s is not covered by the signature", ErrSignatureInvalid, unlisted)
+		}
+		return sig, nil
+	}
+
+	// Explain the mismatch with the manifest that was signed, if it is intact.
+	if signed, err := pkg.ReadResource(ManifestFileName); err == nil && ed25519.Verify(pub, signed, sig.Signature) {
+		old := &Manifest{}
+		if json.Unmarshal(signed, old) == nil {
+			if change := manifestChange(old, m); change != "" {
+				return nil, fmt.Errorf("%w: %s", ErrSignatureInvalid, change)
+			}
+		}
+	}
+	return nil, ErrSignatureInvalid
+}
+
+// unlistedFile returns the first file on disk in the skill directory that m
+// does not list, or "" if there is none. Nested skill packages, directories
+// in defaultIgnores and files matching defaultExcludes are not considered.
+func (p *SkillPackage) unlistedFile(m *Manifest) (string, error) {
+	listed := make(map[string]bool, len(m.Files))
+	for _, f := range m.Files {
+		listed[f.Path] = true
+	}
+	matcher := &ignoreMatcher{}
+	matcher.addPatterns(defaultExcludes)
+	var unlisted string
+	err := fs.WalkDir(p.fsys, p.dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := relativeTo(p.dir, name)
+		if d.IsDir() {
+			if name == p.dir {
+				return nil
+			}
+			if slices.Contains(defaultIgnores, d.Name()) {
+				return fs.SkipDir
+			}
+			if _, err := fs.Stat(p.fsys, path.Join(name, "SKILL.md")); err == nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if listed[rel] || matcher.excluded(rel, false) {
+			return nil
+		}
+		unlisted = rel
+		return fs.SkipAll
+	})
+	return unlisted, err
+}
+
+// manifestChange describes the first difference between the files of two
+// manifests, or returns "" if their files are the same.
+func manifestChange(old, cur *Manifest) string {
+	before := make(map[string]ManifestFile, len(old.Files))
+	for _, f := range old.Files {
+		before[f.Path] = f
+	}
+	for _, f := range cur.Files {
+		prev, ok := before[f.Path]
+		switch {
+		case !ok:
+			return f.Path + " was added"
+		case prev != f:
+			return f.Path + " was modified"
+		}
+		delete(before, f.Path)
+	}
+	for _, f := range old.Files {
+		if _, ok := before[f.Path]; ok {
+			return f.Path + " was removed"
+		}
+	}
+	return ""
+}
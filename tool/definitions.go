@@ -1,131 +1,149 @@
 package tool
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
 	openai "github.com/sashabaranov/go-openai"
 )
 
 // GetBaseTools returns the list of base tools available to all skills.
 func GetBaseTools() []openai.Tool {
-	return []openai.Tool{
-		{
-			Type: openai.ToolTypeFunction,
-			Function: &openai.FunctionDefinition{
-				Name:        "run_shell_script",
-				Description: "Executes a shell script and returns its combined stdout and stderr. Use this for general shell commands.",
-				Parameters: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"scriptPath": map[string]interface{}{
-							"type":        "string",
-							"description": "The path to the shell script to execute.",
-						},
-						"args": map[string]interface{}{
-							"type":        "array",
-							"description": "A list of string arguments to pass to the script.",
-							"items": map[string]interface{}{
-								"type": "string",
-							},
-						},
+	return NewRegistry(BaseTools("")...).Definitions()
+}
+
+// scriptArgs are the arguments of run_shell_script and run_python_script.
+type scriptArgs struct {
+	ScriptPath string   `json:"scriptPath"`
+	Args       []string `json:"args"`
+}
+
+// BaseTools returns the base tools available to all skills. A relative path
+// given to read_file is resolved against dir when the file exists there;
+// with an empty dir, it is resolved against the working directory.
+func BaseTools(dir string) []Tool {
+	return []Tool{
+		NewFunc("run_shell_script",
+			"Executes a shell script and returns its combined stdout and stderr. Use this for general shell commands.",
+			scriptSchema("The path to the shell script to execute."),
+			func(ctx context.Context, args scriptArgs) (Result, error) {
+				out, err := RunShellScript(args.ScriptPath, args.Args)
+				return Result{Output: out}, err
+			}),
+		NewFunc("run_python_script",
+			"Executes a Python script and returns its combined stdout and stderr.",
+			scriptSchema("The path to the Python script to execute."),
+			func(ctx context.Context, args scriptArgs) (Result, error) {
+				out, err := RunPythonScript(args.ScriptPath, args.Args)
+				return Result{Output: out}, err
+			}),
+		NewFunc("read_file",
+			"Reads the content of a file and returns it as a string.",
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"filePath": map[string]interface{}{
+						"type":        "string",
+						"description": "The path to the file to read.",
 					},
-					"required": []string{"scriptPath"},
 				},
+				"required": []string{"filePath"},
 			},
-		},
-		{
-			Type: openai.ToolTypeFunction,
-			Function: &openai.FunctionDefinition{
-				Name:        "run_python_script",
-				Description: "Executes a Python script and returns its combined stdout and stderr.",
-				Parameters: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"scriptPath": map[string]interface{}{
-							"type":        "string",
-							"description": "The path to the Python script to execute.",
-						},
-						"args": map[string]interface{}{
-							"type":        "array",
-							"description": "A list of string arguments to pass to the script.",
-							"items": map[string]interface{}{
-								"type": "string",
-							},
-						},
+			func(ctx context.Context, args struct {
+				FilePath string `json:"filePath"`
+			}) (Result, error) {
+				path := args.FilePath
+				if !filepath.IsAbs(path) && dir != "" {
+					if resolved := filepath.Join(dir, path); fileExists(resolved) {
+						path = resolved
+					}
+				}
+				out, err := ReadFile(path)
+				return Result{Output: out}, err
+			}),
+		NewFunc("write_file",
+			"Writes the given content to a file. If the file does not exist, it will be created. If it exists, its content will be truncated.",
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"filePath": map[string]interface{}{
+						"type":        "string",
+						"description": "The path to the file to write.",
 					},
-					"required": []string{"scriptPath"},
-				},
-			},
-		},
-		{
-			Type: openai.ToolTypeFunction,
-			Function: &openai.FunctionDefinition{
-				Name:        "read_file",
-				Description: "Reads the content of a file and returns it as a string.",
-				Parameters: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"filePath": map[string]interface{}{
-							"type":        "string",
-							"description": "The path to the file to read.",
-						},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "The content to write to the file.",
 					},
-					"required": []string{"filePath"},
 				},
+				"required": []string{"filePath", "content"},
 			},
-		},
-		{
-			Type: openai.ToolTypeFunction,
-			Function: &openai.FunctionDefinition{
-				Name:        "write_file",
-				Description: "Writes the given content to a file. If the file does not exist, it will be created. If it exists, its content will be truncated.",
-				Parameters: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"filePath": map[string]interface{}{
-							"type":        "string",
-							"description": "The path to the file to write.",
-						},
-						"content": map[string]interface{}{
-							"type":        "string",
-							"description": "The content to write to the file.",
-						},
-					},
-					"required": []string{"filePath", "content"},
-				},
+			func(ctx context.Context, args struct {
+				FilePath string `json:"filePath"`
+				Content  string `json:"content"`
+			}) (Result, error) {
+				if err := WriteFile(args.FilePath, args.Content); err != nil {
+					return Result{}, err
+				}
+				return Result{Output: fmt.Sprintf("Successfully wrote to file: %s", args.FilePath)}, nil
+			}),
+		NewFunc("duckduckgo_search",
+			"Performs a DuckDuckGo search for the given query and returns a summary or related topics.",
+			querySchema("The search query."),
+			func(ctx context.Context, args queryArgs) (Result, error) {
+				out, err := DuckDuckGoSearch(args.Query)
+				return Result{Output: out}, err
+			}),
+		NewFunc("wikipedia_search",
+			"Performs a search on Wikipedia for the given query and returns a summary of the relevant entry.",
+			querySchema("The search query for Wikipedia."),
+			func(ctx context.Context, args queryArgs) (Result, error) {
+				out, err := WikipediaSearch(args.Query)
+				return Result{Output: out}, err
+			}),
+	}
+}
+
+// queryArgs are the arguments of the search tools.
+type queryArgs struct {
+	Query string `json:"query"`
+}
+
+func scriptSchema(pathDescription string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"scriptPath": map[string]interface{}{
+				"type":        "string",
+				"description": pathDescription,
 			},
-		},
-		{
-			Type: openai.ToolTypeFunction,
-			Function: &openai.FunctionDefinition{
-				Name:        "duckduckgo_search",
-				Description: "Performs a DuckDuckGo search for the given query and returns a summary or related topics.",
-				Parameters: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"query": map[string]interface{}{
-							"type":        "string",
-							"description": "The search query.",
-						},
-					},
-					"required": []string{"query"},
+			"args": map[string]interface{}{
+				"type":        "array",
+				"description": "A list of string arguments to pass to the script.",
+				"items": map[string]interface{}{
+					"type": "string",
 				},
 			},
 		},
-		{
-			Type: openai.ToolTypeFunction,
-			Function: &openai.FunctionDefinition{
-				Name:        "wikipedia_search",
-				Description: "Performs a search on Wikipedia for the given query and returns a summary of the relevant entry.",
-				Parameters: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"query": map[string]interface{}{
-							"type":        "string",
-							"description": "The search query for Wikipedia.",
-						},
-					},
-					"required": []string{"query"},
-				},
+		"required": []string{"scriptPath"},
+	}
+}
+
+func querySchema(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": description,
 			},
 		},
+		"required": []string{"query"},
 	}
 }
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
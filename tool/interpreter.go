@@ -0,0 +1,218 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Interpreter is a program that runs scripts of one language.
+type Interpreter struct {
+	Name string // Language or program name shown to the model, such as "python"
+	// Command is the program and its leading arguments; the script path and
+	// the script's arguments follow. An empty Command runs the script itself,
+	// which must then be executable.
+	Command []string
+	// Alternatives are programs tried in order in place of Command[0] when it
+	// is not on the PATH, such as "python" for "python3".
+	Alternatives []string
+}
+
+// direct is the interpreter of executables without a known language.
+var direct = Interpreter{Name: "executable"}
+
+// Interpreters maps script file extensions and shebang programs to
+// interpreters. The zero value maps nothing; DefaultInterpreters returns the
+// usual mapping.
+type Interpreters struct {
+	byExt     map[string]Interpreter // Keyed by lowercase extension, with the dot
+	byShebang map[string]Interpreter // Keyed by program name, such as "python3"
+}
+
+// DefaultInterpreters returns the interpreters of common scripting languages.
+func DefaultInterpreters() *Interpreters {
+	python := Interpreter{Name: "python", Command: []string{"python3"}, Alternatives: []string{"python"}}
+	bash := Interpreter{Name: "bash", Command: []string{"bash"}}
+	node := Interpreter{Name: "node", Command: []string{"node"}}
+	r := &Interpreters{}
+	for ext, in := range map[string]Interpreter{
+		".py":   python,
+		".sh":   bash,
+		".bash": bash,
+		".zsh":  {Name: "zsh", Command: []string{"zsh"}},
+		".js":   node,
+		".mjs":  node,
+		".cjs":  node,
+		".ts":   {Name: "typescript", Command: []string{"npx", "tsx"}},
+		".rb":   {Name: "ruby", Command: []string{"ruby"}},
+		".pl":   {Name: "perl", Command: []string{"perl"}},
+		".php":  {Name: "php", Command: []string{"php"}},
+		".lua":  {Name: "lua", Command: []string{"lua"}},
+		".go":   {Name: "go", Command: []string{"go", "run"}},
+		".ps1":  {Name: "powershell", Command: []string{"pwsh", "-File"}},
+		".r":    {Name: "r", Command: []string{"Rscript"}},
+	} {
+		r.SetExtension(ext, in)
+	}
+	for program, in := range map[string]Interpreter{
+		"python":  python,
+		"python3": python,
+		"bash":    bash,
+		"sh":      {Name: "sh", Command: []string{"sh"}},
+		"node":    node,
+		"deno":    {Name: "deno", Command: []string{"deno", "run"}},
+		"bun":     {Name: "bun", Command: []string{"bun", "run"}},
+	} {
+		r.SetShebang(program, in)
+	}
+	return r
+}
+
+// SetExtension maps files with the extension ext, such as ".rb", to in.
+func (r *Interpreters) SetExtension(ext string, in Interpreter) {
+	if r.byExt == nil {
+		r.byExt = make(map[string]Interpreter)
+	}
+	r.byExt[strings.ToLower(ext)] = in
+}
+
+// SetShebang maps scripts whose shebang line runs program, directly or
+// through env, to in.
+func (r *Interpreters) SetShebang(program string, in Interpreter) {
+	if r.byShebang == nil {
+		r.byShebang = make(map[string]Interpreter)
+	}
+	r.byShebang[program] = in
+}
+
+// Remove drops the mapping of an extension (starting with '.') or shebang program.
+func (r *Interpreters) Remove(key string) {
+	if strings.HasPrefix(key, ".") {
+		delete(r.byExt, strings.ToLower(key))
+	} else {
+		delete(r.byShebang, key)
+	}
+}
+
+// Set parses a mapping in the form "KEY=COMMAND", where KEY is an extension
+// such as ".ts" or a shebang program such as "node", and COMMAND is the
+// interpreter command line, such as "deno run". An empty COMMAND removes the
+// mapping, so that such scripts are not run.
+func (r *Interpreters) Set(mapping string) error {
+	key, command, ok := strings.Cut(mapping, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || key == "." {
+		return fmt.Errorf("invalid interpreter mapping '%s' (expected .EXT=COMMAND or PROGRAM=COMMAND)", mapping)
+	}
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		r.Remove(key)
+		return nil
+	}
+	in := Interpreter{Name: path.Base(fields[0]), Command: fields}
+	if strings.HasPrefix(key, ".") {
+		r.SetExtension(key, in)
+	} else {
+		r.SetShebang(key, in)
+	}
+	return nil
+}
+
+// Extensions returns the mapped extensions, sorted.
+func (r *Interpreters) Extensions() []string {
+	exts := make([]string, 0, len(r.byExt))
+	for ext := range r.byExt {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// Resolve returns the interpreter of the script at name, given the start of
+// its content and whether it is executable. A shebang line decides first: a
+// mapped program is replaced by its interpreter, and any other runs as
+// written. Then the extension decides. An executable without an extension
+// is run directly. Other files, such as documentation and data, have no
+// interpreter.
+func (r *Interpreters) Resolve(name string, head []byte, executable bool) (Interpreter, bool) {
+	if line, ok := bytes.CutPrefix(head, []byte("#!")); ok {
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end]
+		}
+		if fields := shebangCommand(string(line)); len(fields) > 0 {
+			if in, ok := r.byShebang[path.Base(fields[0])]; ok {
+				return in, true
+			}
+			if executable {
+				return Interpreter{Name: path.Base(fields[0])}, true
+			}
+			return Interpreter{Name: path.Base(fields[0]), Command: fields}, true
+		}
+	}
+	ext := strings.ToLower(path.Ext(name))
+	if in, ok := r.byExt[ext]; ok {
+		return in, true
+	}
+	if executable && ext == "" {
+		return direct, true
+	}
+	return Interpreter{}, false
+}
+
+// shebangCommand returns the command of a shebang line, looking through
+// "/usr/bin/env" and its -S option to the program it starts.
+func shebangCommand(line string) []string {
+	fields := strings.Fields(strings.TrimSpace(line))
+	if len(fields) > 0 && path.Base(fields[0]) == "env" {
+		fields = fields[1:]
+		if len(fields) > 0 && fields[0] == "-S" {
+			fields = fields[1:]
+		}
+	}
+	return fields
+}
+
+// RunScript runs a script with an interpreter and returns its combined
+// stdout and stderr.
+func RunScript(in Interpreter, scriptPath string, args []string) (string, error) {
+	return RunScriptContext(context.Background(), in, scriptPath, args)
+}
+
+// ScriptCommand returns the command that runs a script with an interpreter,
+// killed if ctx is done before it exits. When Command[0] is not on the PATH,
+// the first of the Alternatives that is takes its place.
+func ScriptCommand(ctx context.Context, in Interpreter, scriptPath string, args []string) *exec.Cmd {
+	if len(in.Command) == 0 {
+		return exec.CommandContext(ctx, scriptPath, args...)
+	}
+	program := in.Command[0]
+	if _, err := exec.LookPath(program); err != nil {
+		for _, alt := range in.Alternatives {
+			if _, err := exec.LookPath(alt); err == nil {
+				program = alt
+				break
+			}
+		}
+	}
+	cmdArgs := append(append(append([]string(nil), in.Command[1:]...), scriptPath), args...)
+	return exec.CommandContext(ctx, program, cmdArgs...)
+}
+
+// RunScriptContext is like RunScript, but kills the script if ctx is done
+// before it exits.
+func RunScriptContext(ctx context.Context, in Interpreter, scriptPath string, args []string) (string, error) {
+	cmd := ScriptCommand(ctx, in, scriptPath, args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run %s script '%s': %w\nStdout: %s\nStderr: %s", in.Name, scriptPath, err, stdout.String(), stderr.String())
+	}
+
+	return stdout.String() + stderr.String(), nil
+}
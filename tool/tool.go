@@ -0,0 +1,167 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Result is the outcome of a successful tool call.
+type Result struct {
+	Output string // Text returned to the model
+}
+
+// Tool is a function the model can call.
+type Tool interface {
+	// Name is the function name the model calls the tool by.
+	Name() string
+	// Description tells the model what the tool does and when to use it.
+	Description() string
+	// Schema is the JSON schema of the tool's arguments.
+	Schema() map[string]interface{}
+	// Execute runs the tool with the JSON arguments of a call.
+	Execute(ctx context.Context, args json.RawMessage) (Result, error)
+}
+
+// funcTool is a Tool implemented by a function.
+type funcTool struct {
+	name        string
+	description string
+	schema      map[string]interface{}
+	run         func(ctx context.Context, args json.RawMessage) (Result, error)
+}
+
+func (f *funcTool) Name() string                   { return f.name }
+func (f *funcTool) Description() string            { return f.description }
+func (f *funcTool) Schema() map[string]interface{} { return f.schema }
+
+func (f *funcTool) Execute(ctx context.Context, args json.RawMessage) (Result, error) {
+	return f.run(ctx, args)
+}
+
+// NewFunc returns a Tool that calls run with the arguments of each call,
+// decoded from JSON into a value of type A.
+func NewFunc[A any](name, description string, schema map[string]interface{}, run func(ctx context.Context, args A) (Result, error)) Tool {
+	return &funcTool{
+		name:        name,
+		description: description,
+		schema:      schema,
+		run: func(ctx context.Context, raw json.RawMessage) (Result, error) {
+			var args A
+			if len(raw) > 0 {
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return Result{}, fmt.Errorf("failed to unmarshal %s arguments: %w", name, err)
+				}
+			}
+			return run(ctx, args)
+		},
+	}
+}
+
+// Definition returns the OpenAI function definition of a tool.
+func Definition(t Tool) openai.Tool {
+	return openai.Tool{
+		Type: openai.ToolTypeFunction,
+		Function: &openai.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  t.Schema(),
+		},
+	}
+}
+
+// Registry holds tools by name, in the order they were registered. It is
+// safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+	order []string
+}
+
+// NewRegistry returns a registry holding tools. It panics if two of them
+// share a name.
+func NewRegistry(tools ...Tool) *Registry {
+	r := &Registry{tools: make(map[string]Tool)}
+	for _, t := range tools {
+		if err := r.Register(t); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a tool. It fails if the tool has no name or another tool
+// has its name.
+func (r *Registry) Register(t Tool) error {
+	name := t.Name()
+	if name == "" {
+		return fmt.Errorf("tool has no name")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[name]; ok {
+		return fmt.Errorf("tool '%s' is already registered", name)
+	}
+	r.tools[name] = t
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Unregister removes the tool called name, reporting whether there was one.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[name]; !ok {
+		return false
+	}
+	delete(r.tools, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Lookup returns the tool called name.
+func (r *Registry) Lookup(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Tools returns the registered tools in registration order.
+func (r *Registry) Tools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name])
+	}
+	return tools
+}
+
+// Definitions returns the OpenAI function definitions of the registered
+// tools, in registration order.
+func (r *Registry) Definitions() []openai.Tool {
+	tools := r.Tools()
+	defs := make([]openai.Tool, 0, len(tools))
+	for _, t := range tools {
+		defs = append(defs, Definition(t))
+	}
+	return defs
+}
+
+// Execute runs the tool called name with the JSON arguments of a call.
+func (r *Registry) Execute(ctx context.Context, name string, args json.RawMessage) (Result, error) {
+	t, ok := r.Lookup(name)
+	if !ok {
+		return Result{}, fmt.Errorf("unknown tool: %s", name)
+	}
+	return t.Execute(ctx, args)
+}
@@ -1,6 +1,10 @@
 package goskills
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -11,74 +15,317 @@ import (
 
 // GenerateToolDefinitions generates the list of OpenAI tools for a given skill.
 // It returns the tool definitions and a map of tool names to script paths for execution.
-func GenerateToolDefinitions(skill SkillPackage) ([]openai.Tool, map[string]string) {
-	var tools []openai.Tool
-	scriptMap := make(map[string]string)
+// Scripts that are symbolic links resolving outside the skill are not exposed unless
+// the skill was parsed with SymlinkAllow. Scripts that declare their interface
+// get a typed parameter schema; see SkillPackage.ScriptSpec. The tools are
+// those of SkillTools.
+func GenerateToolDefinitions(skill SkillPackage, opts ...ToolOption) ([]openai.Tool, map[string]string) {
+	tools, scripts := GenerateTools(skill, opts...)
+	scriptMap := make(map[string]string, len(scripts))
+	for name, s := range scripts {
+		scriptMap[name] = s.Path
+	}
+	return tools, scriptMap
+}
+
+// ScriptTool is a generated tool that runs a script of a skill.
+type ScriptTool struct {
+	Path        string           // OS path of the script
+	Script      string           // Path of the script relative to the skill root
+	Spec        *ScriptSpec      // Interface declared by the script; nil if it declares none
+	Interpreter tool.Interpreter // What runs the script
+	name        string
+	description string
+}
+
+// Args maps the JSON arguments of a call to the tool to the script's command line.
+func (t *ScriptTool) Args(arguments string) ([]string, error) {
+	return t.Spec.Args(arguments)
+}
 
-	// 1. Base Tools
-	baseTools := tool.GetBaseTools()
+func (t *ScriptTool) Name() string                   { return t.name }
+func (t *ScriptTool) Description() string            { return t.description }
+func (t *ScriptTool) Schema() map[string]interface{} { return t.Spec.Schema() }
+
+// Execute runs the script with the command line mapped from args.
+func (t *ScriptTool) Execute(ctx context.Context, args json.RawMessage) (tool.Result, error) {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	cmdArgs, err := t.Args(string(args))
+	if err != nil {
+		return tool.Result{}, fmt.Errorf("invalid arguments for %s: %w", t.name, err)
+	}
+	out, err := tool.RunScriptContext(ctx, t.Interpreter, t.Path, cmdArgs)
+	return tool.Result{Output: out}, err
+}
 
-	if len(skill.Meta.AllowedTools) > 0 {
-		allowedMap := make(map[string]bool)
-		for _, t := range skill.Meta.AllowedTools {
-			allowedMap[t] = true
+// GenerateTools is like GenerateToolDefinitions, but describes the script
+// behind each script tool, so that the named parameters of a call can be
+// mapped back to command-line arguments with ScriptTool.Args.
+func GenerateTools(skill SkillPackage, opts ...ToolOption) ([]openai.Tool, map[string]*ScriptTool) {
+	registry := SkillTools(skill, opts...)
+	scripts := make(map[string]*ScriptTool)
+	for _, t := range registry.Tools() {
+		if s, ok := t.(*ScriptTool); ok {
+			scripts[s.Name()] = s
 		}
+	}
+	return registry.Definitions(), scripts
+}
+
+// SkillTools returns a registry of the tools a skill may use: the base
+// tools, or those given with WithBaseTools, followed by a ScriptTool per
+// script, as far as its allowed-tools and disallowed-tools permit. Script
+// tools are only generated for skills parsed from an OS directory, since
+// scripts read from another fs.FS cannot be run. Reference tools are not
+// included; see ReferenceTools.
+func SkillTools(skill SkillPackage, opts ...ToolOption) *tool.Registry {
+	o := newToolOptions(opts)
+	registry := tool.NewRegistry()
 
-		for _, t := range baseTools {
-			if allowedMap[t.Function.Name] {
-				tools = append(tools, t)
-			}
+	// 1. Base Tools, as far as allowed-tools and disallowed-tools permit
+	policy := skill.Meta.ToolPolicy()
+	baseTools := o.baseTools(skill)
+	for _, t := range baseTools {
+		if policy.Exposes(t.Name()) {
+			registry.Register(t)
 		}
-	} else {
-		tools = append(tools, baseTools...)
 	}
 
-	// 2. Script Tools
-	for _, scriptRelPath := range skill.Resources.Scripts {
-		toolDef, toolName := generateScriptTool(skill.Path, scriptRelPath)
-		tools = append(tools, toolDef)
-		scriptMap[toolName] = filepath.Join(skill.Path, scriptRelPath)
+	// 2. Script Tools, leaving out links that resolve outside the skill and
+	// files that no interpreter runs
+	if !skill.native {
+		return registry
+	}
+	namer := newToolNamer(baseTools)
+	for _, script := range skill.exposedScripts(o) {
+		// A script whose spec cannot be read keeps the untyped schema;
+		// Validate reports the problem.
+		spec, _ := skill.ScriptSpec(script.path, opts...)
+		toolName := namer.name("run_", script.path)
+		if !policy.Exposes(toolName) {
+			continue
+		}
+		registry.Register(&ScriptTool{
+			Path:        filepath.Join(skill.Path, script.path),
+			Script:      script.path,
+			Spec:        spec,
+			Interpreter: script.interpreter,
+			name:        toolName,
+			description: scriptToolDescription(script.path, script.interpreter, spec),
+		})
 	}
 
-	return tools, scriptMap
+	return registry
 }
 
-func generateScriptTool(skillPath, scriptRelPath string) (openai.Tool, string) {
-	// Normalize name: replace non-alphanumeric with underscore
-	safeName := strings.Map(func(r rune) rune {
+// scriptHeadSize is how much of a script is read to find its shebang line.
+const scriptHeadSize = 256
+
+// exposedScript is a script that may become a tool, with what runs it.
+type exposedScript struct {
+	path        string
+	interpreter tool.Interpreter
+}
+
+// exposedScripts returns the scripts that may become tools: those that an
+// interpreter of o runs, except symbolic links resolving outside the skill
+// unless those were allowed.
+func (p *SkillPackage) exposedScripts(o *toolOptions) []exposedScript {
+	var scripts []exposedScript
+	for _, f := range p.Resources.Files {
+		if f.Kind != KindScript || !p.contentAllowed(f) {
+			continue
+		}
+		head, err := p.resourceHead(f.Path, scriptHeadSize)
+		if err != nil {
+			continue
+		}
+		if in, ok := o.interpreters.Resolve(f.Path, head, f.Executable); ok {
+			scripts = append(scripts, exposedScript{path: f.Path, interpreter: in})
+		}
+	}
+	return scripts
+}
+
+// MaxToolNameLength is the longest function name most model providers accept.
+const MaxToolNameLength = 64
+
+// toolNameHashLen is the number of hex digits of the path hash that
+// disambiguates a tool name.
+const toolNameHashLen = 8
+
+// ToolNameCollision records two files of a skill whose tool names would be
+// the same once their paths are normalized, such as scripts/a-b.py and
+// scripts/a_b.py. The first file keeps the name; the second is exposed under
+// a name with a hash of its path appended.
+type ToolNameCollision struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`    // File that keeps Name
+	Other   string `json:"other"`   // File whose tool was renamed
+	Renamed string `json:"renamed"` // Name given to the tool of Other
+}
+
+func (c ToolNameCollision) String() string {
+	return fmt.Sprintf("'%s' and '%s' both map to tool name '%s'; '%s' is exposed as '%s'", c.Path, c.Other, c.Name, c.Other, c.Renamed)
+}
+
+// toolNamer assigns unique, length-limited tool names to the files of a skill.
+type toolNamer struct {
+	used       map[string]string // Tool names given out, with the file they were given to
+	collisions []ToolNameCollision
+}
+
+// newToolNamer returns a toolNamer that avoids the names of tools.
+func newToolNamer(tools []tool.Tool) *toolNamer {
+	n := &toolNamer{used: make(map[string]string)}
+	for _, t := range tools {
+		n.used[t.Name()] = ""
+	}
+	return n
+}
+
+// name returns the tool name for the file at relPath: prefix followed by the
+// path with every character outside [A-Za-z0-9] replaced by an underscore.
+// A name longer than MaxToolNameLength, or already given to another file, is
+// cut short and ends in a hash of the path instead, so the same path always
+// gets the same name.
+func (n *toolNamer) name(prefix, relPath string) string {
+	base := prefix + safeToolName(relPath)
+	name := base
+	if len(name) > MaxToolNameLength {
+		name = hashedToolName(base, relPath)
+	}
+	if owner, taken := n.used[name]; taken {
+		renamed := hashedToolName(base, relPath)
+		if owner != "" {
+			n.collisions = append(n.collisions, ToolNameCollision{Name: name, Path: owner, Other: relPath, Renamed: renamed})
+		}
+		name = renamed
+	}
+	n.used[name] = relPath
+	return name
+}
+
+// hashedToolName shortens base as needed to append an underscore and a hash
+// of relPath within MaxToolNameLength.
+func hashedToolName(base, relPath string) string {
+	sum := sha256.Sum256([]byte(filepath.ToSlash(relPath)))
+	suffix := "_" + hex.EncodeToString(sum[:])[:toolNameHashLen]
+	if max := MaxToolNameLength - len(suffix); len(base) > max {
+		base = base[:max]
+	}
+	return base + suffix
+}
+
+// ToolNameCollisions returns the scripts and reference documents of a skill
+// whose tool names collide, as GenerateTools, given the same options, and
+// GenerateReferenceTools resolve them.
+func ToolNameCollisions(skill SkillPackage, opts ...ToolOption) []ToolNameCollision {
+	o := newToolOptions(opts)
+	scripts := newToolNamer(o.baseTools(skill))
+	for _, s := range skill.exposedScripts(o) {
+		scripts.name("run_", s.path)
+	}
+	refs := newToolNamer(o.baseTools(skill))
+	for _, f := range skill.referenceDocuments() {
+		refs.name("read_", f)
+	}
+	return append(scripts.collisions, refs.collisions...)
+}
+
+// safeToolName normalizes a relative path for use in a tool name by replacing
+// every non-alphanumeric character with an underscore.
+func safeToolName(relPath string) string {
+	return strings.Map(func(r rune) rune {
 		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
 			return r
 		}
 		return '_'
-	}, scriptRelPath)
-	toolName := "run_" + safeName
-
-	// Determine type based on extension
-	ext := filepath.Ext(scriptRelPath)
-	var description string
-	if ext == ".py" {
-		description = fmt.Sprintf("Executes the python script '%s'.", scriptRelPath)
-	} else {
-		description = fmt.Sprintf("Executes the shell script '%s'.", scriptRelPath)
-	}
-
-	return openai.Tool{
-		Type: openai.ToolTypeFunction,
-		Function: &openai.FunctionDefinition{
-			Name:        toolName,
-			Description: description,
-			Parameters: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"args": map[string]interface{}{
-						"type":        "array",
-						"description": "Arguments to pass to the script.",
-						"items": map[string]interface{}{
-							"type": "string",
-						},
-					},
-				},
-			},
-		},
-	}, toolName
+	}, relPath)
+}
+
+// scriptToolDescription returns the description of the tool of a script.
+func scriptToolDescription(scriptRelPath string, in tool.Interpreter, spec *ScriptSpec) string {
+	if spec != nil && spec.Description != "" {
+		return fmt.Sprintf("%s (script '%s')", strings.TrimSuffix(spec.Description, "."), scriptRelPath)
+	}
+	return fmt.Sprintf("Executes the %s script '%s'.", in.Name, scriptRelPath)
+}
+
+// ReferenceTool is a generated tool that loads a reference document of a skill.
+type ReferenceTool struct {
+	Path  string // Path of the document relative to the skill root
+	name  string
+	skill *SkillPackage
+}
+
+func (t *ReferenceTool) Name() string { return t.name }
+
+func (t *ReferenceTool) Description() string {
+	return fmt.Sprintf("Loads the reference document '%s' of the '%s' skill. Call it when the skill instructions point to this file.", t.Path, t.skill.Meta.Name)
+}
+
+func (t *ReferenceTool) Schema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+// Execute returns the content of the document; the tool takes no arguments.
+func (t *ReferenceTool) Execute(ctx context.Context, args json.RawMessage) (tool.Result, error) {
+	content, err := t.skill.ReadResource(t.Path)
+	if err != nil {
+		return tool.Result{}, err
+	}
+	return tool.Result{Output: string(content)}, nil
+}
+
+// ReferenceTools returns one parameterless tool per markdown reference file
+// of a skill, so the model can load those documents on demand instead of
+// having them inlined in the prompt, as far as its allowed-tools and
+// disallowed-tools permit.
+func ReferenceTools(skill SkillPackage, opts ...ToolOption) []*ReferenceTool {
+	o := newToolOptions(opts)
+	var tools []*ReferenceTool
+	policy := skill.Meta.ToolPolicy()
+	namer := newToolNamer(o.baseTools(skill))
+	for _, ref := range skill.referenceDocuments() {
+		toolName := namer.name("read_", ref)
+		if !policy.Exposes(toolName) {
+			continue
+		}
+		tools = append(tools, &ReferenceTool{Path: ref, name: toolName, skill: &skill})
+	}
+	return tools
+}
+
+// GenerateReferenceTools returns the definitions of the ReferenceTools of a
+// skill and a map of tool names to reference paths relative to the skill root.
+func GenerateReferenceTools(skill SkillPackage, opts ...ToolOption) ([]openai.Tool, map[string]string) {
+	var tools []openai.Tool
+	refMap := make(map[string]string)
+	for _, t := range ReferenceTools(skill, opts...) {
+		tools = append(tools, tool.Definition(t))
+		refMap[t.Name()] = t.Path
+	}
+	return tools, refMap
+}
+
+// referenceDocuments returns the markdown reference files of a skill that
+// may be loaded on demand.
+func (p *SkillPackage) referenceDocuments() []string {
+	var refs []string
+	for _, f := range p.Resources.Files {
+		if f.Kind != KindReference || !p.contentAllowed(f) {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(f.Path))
+		if ext == ".md" || ext == ".markdown" {
+			refs = append(refs, f.Path)
+		}
+	}
+	return refs
 }
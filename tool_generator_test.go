@@ -0,0 +1,212 @@
+package goskills
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smallnest/goskills/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTools_NameCollisions(t *testing.T) {
+	long := "scripts/" + strings.Repeat("very-long-directory-name/", 4) + "convert.py"
+	fsys := fstest.MapFS{
+		"tools/SKILL.md":          {Data: []byte("---\nname: tools\ndescription: Colliding scripts.\n---\n")},
+		"tools/scripts/a-b.py":    {Data: []byte("pass")},
+		"tools/scripts/a_b.py":    {Data: []byte("pass")},
+		"tools/scripts/a.b.py":    {Data: []byte("pass")},
+		"tools/" + long:           {Data: []byte("pass")},
+		"tools/references/x-y.md": {Data: []byte("# X")},
+		"tools/references/x_y.md": {Data: []byte("# Y")},
+	}
+	pkg, err := ParseSkillPackage(filepath.Join(writeFS(t, fsys), "tools"))
+	require.NoError(t, err)
+
+	_, scripts := GenerateTools(*pkg)
+	byPath := make(map[string]string)
+	for name, s := range scripts {
+		assert.LessOrEqual(t, len(name), MaxToolNameLength, name)
+		byPath[s.Script] = name
+	}
+	require.Len(t, byPath, 4, "no script is lost to a collision")
+	assert.Equal(t, "run_scripts_a_b_py", byPath["scripts/a-b.py"], "the first path keeps the plain name")
+	assert.Regexp(t, `^run_scripts_a_b_py_[0-9a-f]{8}$`, byPath["scripts/a.b.py"])
+	assert.Regexp(t, `^run_scripts_a_b_py_[0-9a-f]{8}$`, byPath["scripts/a_b.py"])
+	assert.NotEqual(t, byPath["scripts/a.b.py"], byPath["scripts/a_b.py"])
+	assert.Len(t, byPath[long], MaxToolNameLength)
+	assert.True(t, strings.HasPrefix(byPath[long], "run_scripts_very_long_directory_name_"))
+
+	// Names do not depend on generation order.
+	_, again := GenerateTools(*pkg)
+	for name, s := range again {
+		assert.Equal(t, byPath[s.Script], name)
+	}
+
+	_, refMap := GenerateReferenceTools(*pkg)
+	assert.Len(t, refMap, 2)
+
+	collisions := ToolNameCollisions(*pkg)
+	require.Len(t, collisions, 3)
+	assert.Equal(t, "scripts/a-b.py", collisions[0].Path)
+	assert.Equal(t, "scripts/a.b.py", collisions[0].Other)
+	assert.Equal(t, byPath["scripts/a.b.py"], collisions[0].Renamed)
+	assert.Equal(t, "references/x-y.md", collisions[2].Path)
+
+	var rules []string
+	for _, d := range Validate(pkg) {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{RuleToolNameCollision, RuleToolNameCollision, RuleToolNameCollision}, rules)
+}
+
+// writeFS writes the files of fsys to a temporary directory and returns it.
+func writeFS(t *testing.T, fsys fstest.MapFS) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, f := range fsys {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		mode := f.Mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		require.NoError(t, os.WriteFile(path, f.Data, mode))
+	}
+	return root
+}
+
+func TestSkillTools_NotNative(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tools/SKILL.md":         {Data: []byte("---\nname: tools\ndescription: Embedded scripts.\n---\n")},
+		"tools/scripts/build.sh": {Data: []byte("#!/bin/sh\necho built\n"), Mode: 0o755},
+	}
+	pkg, err := ParseSkillPackageFS(fsys, "tools")
+	require.NoError(t, err)
+	require.Equal(t, []string{"scripts/build.sh"}, pkg.Resources.Scripts)
+
+	tools, scripts := GenerateTools(*pkg)
+	assert.Empty(t, scripts, "scripts read from an fs.FS cannot be run")
+	assert.Len(t, tools, len(tool.BaseTools("")))
+}
+
+func TestGenerateTools_ExampleSkills(t *testing.T) {
+	validName := regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	skills, err := ParseSkillPackages("./examples/skills")
+	require.NoError(t, err)
+	for _, skill := range skills {
+		tools, scripts := GenerateTools(*skill)
+		refTools, _ := GenerateReferenceTools(*skill)
+		seen := make(map[string]bool)
+		for _, tool := range append(tools, refTools...) {
+			name := tool.Function.Name
+			assert.Regexp(t, validName, name)
+			assert.False(t, seen[name], "duplicate tool %s in %s", name, skill.Meta.Name)
+			seen[name] = true
+		}
+		assert.Len(t, scripts, len(skill.exposedScripts(newToolOptions(nil))), "every script of %s has a tool", skill.Meta.Name)
+		assert.Empty(t, ToolNameCollisions(*skill), skill.Meta.Name)
+		for _, s := range scripts {
+			top, _, _ := strings.Cut(s.Script, "/")
+			assert.Equal(t, KindScript, resourceDirKinds[top], "%s of %s is outside a script directory", s.Script, skill.Meta.Name)
+		}
+	}
+}
+
+func TestGenerateTools_Interpreters(t *testing.T) {
+	pkg := scriptSpecSkill(t, map[string]string{
+		"scripts/greet.sh":         "#!/bin/bash\necho \"hello $1\"\n",
+		"scripts/build.js":         "console.log('built')\n",
+		"scripts/check":            "#!/usr/bin/env -S ruby -w\nputs 'ok'\n",
+		"scripts/fetch":            "#!/usr/bin/env python3\nprint('ok')\n",
+		"scripts/README.md":        "# Scripts\n",
+		"scripts/requirements.txt": "requests\n",
+		"scripts/sample.xml":       "<sample/>\n",
+	})
+
+	_, scripts := GenerateTools(*pkg)
+	interpreters := make(map[string]string)
+	for _, s := range scripts {
+		interpreters[s.Script] = s.Interpreter.Name
+	}
+	assert.Equal(t, map[string]string{
+		"scripts/greet.sh": "bash",
+		"scripts/build.js": "node",
+		"scripts/check":    "ruby",
+		"scripts/fetch":    "python",
+	}, interpreters, "documentation and data files are not tools")
+
+	custom := tool.DefaultInterpreters()
+	require.NoError(t, custom.Set(".js=deno run"))
+	require.NoError(t, custom.Set("python3="))
+	require.NoError(t, custom.Set(".xml=xmllint --noout"))
+	assert.Error(t, custom.Set("deno run"))
+	_, scripts = GenerateTools(*pkg, WithInterpreters(custom))
+	require.Contains(t, scripts, "run_scripts_build_js")
+	assert.Equal(t, []string{"deno", "run"}, scripts["run_scripts_build_js"].Interpreter.Command)
+	require.Contains(t, scripts, "run_scripts_fetch", "an unmapped shebang program runs the executable itself")
+	assert.Empty(t, scripts["run_scripts_fetch"].Interpreter.Command)
+	assert.Contains(t, scripts, "run_scripts_sample_xml")
+
+	greet := scripts["run_scripts_greet_sh"]
+	args, err := greet.Args(`{"args": ["world"]}`)
+	require.NoError(t, err)
+	out, err := tool.RunScript(greet.Interpreter, greet.Path, args)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", out)
+}
+
+func TestSkillTools_Registry(t *testing.T) {
+	pkg := scriptSpecSkill(t, map[string]string{
+		"scripts/greet.sh":    "#!/bin/bash\n# @param {string} name Who to greet\necho \"hello $1\"\n",
+		"references/guide.md": "# Guide\n",
+	})
+
+	type echoArgs struct {
+		Text string `json:"text"`
+	}
+	echo := tool.NewFunc("echo", "Echoes its input.", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args echoArgs) (tool.Result, error) {
+			return tool.Result{Output: args.Text}, nil
+		})
+	base := tool.NewRegistry(echo)
+	assert.ErrorContains(t, base.Register(echo), "tool 'echo' is already registered")
+
+	registry := SkillTools(*pkg, WithBaseTools(base))
+	var names []string
+	for _, d := range registry.Definitions() {
+		names = append(names, d.Function.Name)
+	}
+	assert.Equal(t, []string{"echo", "run_scripts_greet_sh"}, names, "custom tools replace the base tools")
+
+	ctx := context.Background()
+	result, err := registry.Execute(ctx, "echo", json.RawMessage(`{"text": "hi"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "hi", result.Output)
+
+	result, err = registry.Execute(ctx, "run_scripts_greet_sh", json.RawMessage(`{"name": "world"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", result.Output)
+	_, err = registry.Execute(ctx, "run_scripts_greet_sh", json.RawMessage(`{}`))
+	assert.ErrorContains(t, err, "missing required parameter 'name'")
+	_, err = registry.Execute(ctx, "read_file", nil)
+	assert.ErrorContains(t, err, "unknown tool: read_file")
+
+	refs := ReferenceTools(*pkg)
+	require.Len(t, refs, 1)
+	require.NoError(t, registry.Register(refs[0]))
+	result, err = registry.Execute(ctx, "read_references_guide_md", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "# Guide\n", result.Output)
+
+	assert.True(t, registry.Unregister("echo"))
+	assert.False(t, registry.Unregister("echo"))
+	_, ok := registry.Lookup("echo")
+	assert.False(t, ok)
+}
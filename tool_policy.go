@@ -0,0 +1,219 @@
+package goskills
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/smallnest/goskills/tool"
+)
+
+// toolAliases maps the tool names used in Claude Code's allowed-tools to
+// the names of the tools they correspond to here.
+var toolAliases = map[string][]string{
+	"Bash":      {"run_*"}, // Base script runners and generated script tools
+	"Read":      {"read_*"},
+	"Write":     {"write_file"},
+	"Edit":      {"write_file"},
+	"WebSearch": {"duckduckgo_search", "wikipedia_search"},
+}
+
+// ToolRule is one entry of allowed-tools or disallowed-tools. Name is a tool
+// name, a glob over tool names such as "run_scripts_*", or a Claude Code tool
+// name such as "Bash". An entry written as "Name(pattern)" only matches calls
+// whose command matches pattern: "git:*" matches the command "git" and any
+// command starting with "git ", and otherwise '*' matches any text.
+type ToolRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern,omitempty"` // Empty to match every call
+	raw     string
+	pattern *regexp.Regexp
+}
+
+// ParseToolRule parses an allowed-tools or disallowed-tools entry.
+func ParseToolRule(entry string) (ToolRule, error) {
+	rule := ToolRule{raw: entry, Name: strings.TrimSpace(entry)}
+	if open := strings.IndexByte(rule.Name, '('); open >= 0 {
+		if !strings.HasSuffix(rule.Name, ")") {
+			return ToolRule{}, fmt.Errorf("tool rule '%s' has an unclosed argument pattern", entry)
+		}
+		rule.Pattern = strings.TrimSpace(rule.Name[open+1 : len(rule.Name)-1])
+		rule.Name = strings.TrimSpace(rule.Name[:open])
+		if rule.Pattern == "" {
+			return ToolRule{}, fmt.Errorf("tool rule '%s' has an empty argument pattern", entry)
+		}
+		rule.pattern = compileCommandPattern(rule.Pattern)
+	}
+	if rule.Name == "" {
+		return ToolRule{}, fmt.Errorf("tool rule '%s' has no tool name", entry)
+	}
+	if _, err := path.Match(rule.Name, ""); err != nil {
+		return ToolRule{}, fmt.Errorf("tool rule '%s': %w", entry, err)
+	}
+	return rule, nil
+}
+
+// compileCommandPattern compiles an argument pattern of a ToolRule.
+func compileCommandPattern(pattern string) *regexp.Regexp {
+	if prefix, ok := strings.CutSuffix(pattern, ":*"); ok {
+		return regexp.MustCompile(`(?s)^` + regexp.QuoteMeta(prefix) + `(?:\s.*)?$`)
+	}
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile(`(?s)^` + strings.Join(parts, `.*`) + `$`)
+}
+
+func (r ToolRule) String() string {
+	return r.raw
+}
+
+// MatchesTool reports whether the rule names the tool, whatever its arguments.
+func (r ToolRule) MatchesTool(name string) bool {
+	patterns, ok := toolAliases[r.Name]
+	if !ok {
+		patterns = []string{r.Name}
+	}
+	for _, p := range patterns {
+		if matched, _ := path.Match(p, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the rule matches a call to the tool name with the
+// given command; see ToolCommand.
+func (r ToolRule) Matches(name, command string) bool {
+	if !r.MatchesTool(name) {
+		return false
+	}
+	return r.pattern == nil || r.pattern.MatchString(command)
+}
+
+// ToolPolicy decides which tools a skill may use. Without allow rules every
+// tool is allowed; otherwise a call must match one of them. A call matching
+// a deny rule is refused either way.
+type ToolPolicy struct {
+	Allow []ToolRule `json:"allow,omitempty"`
+	Deny  []ToolRule `json:"deny,omitempty"`
+}
+
+// NewToolPolicy parses the allowed-tools and disallowed-tools entries of a
+// skill. Entries that cannot be parsed are left out of the policy and
+// returned as one error.
+func NewToolPolicy(allowed, disallowed []string) (*ToolPolicy, error) {
+	p := &ToolPolicy{}
+	var errs []error
+	for _, entry := range allowed {
+		rule, err := ParseToolRule(entry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("allowed-tools: %w", err))
+			continue
+		}
+		p.Allow = append(p.Allow, rule)
+	}
+	for _, entry := range disallowed {
+		rule, err := ParseToolRule(entry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("disallowed-tools: %w", err))
+			continue
+		}
+		p.Deny = append(p.Deny, rule)
+	}
+	return p, errors.Join(errs...)
+}
+
+// ToolPolicy returns the policy given by the skill's allowed-tools and
+// disallowed-tools. Validate reports the entries that cannot be parsed.
+func (m SkillMeta) ToolPolicy() *ToolPolicy {
+	p, _ := NewToolPolicy(m.AllowedTools, m.DisallowedTools)
+	return p
+}
+
+// Exposes reports whether the tool should be offered at all: some calls to
+// it may be allowed. A tool that is only denied for some commands is still
+// offered; Check refuses those calls.
+func (p *ToolPolicy) Exposes(name string) bool {
+	if p == nil {
+		return true
+	}
+	for _, r := range p.Deny {
+		if r.pattern == nil && r.MatchesTool(name) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, r := range p.Allow {
+		if r.MatchesTool(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error unless a call to the tool name with the given
+// command is allowed.
+func (p *ToolPolicy) Check(name, command string) error {
+	if p == nil {
+		return nil
+	}
+	for _, r := range p.Deny {
+		if r.Matches(name, command) {
+			return fmt.Errorf("tool '%s' is denied by disallowed-tools entry '%s'", name, r)
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, r := range p.Allow {
+		if r.Matches(name, command) {
+			return nil
+		}
+	}
+	if p.Exposes(name) {
+		return fmt.Errorf("command '%s' of tool '%s' is not permitted by allowed-tools", command, name)
+	}
+	return fmt.Errorf("tool '%s' is not in allowed-tools", name)
+}
+
+// ToolCommand returns the command of a call to t, which the argument
+// patterns of tool rules are matched against: for a script tool of the
+// skill, the script path relative to the skill root followed by its
+// arguments; for the base script runners, the script path followed by its
+// arguments; for file tools, the file path; for search tools, the query.
+// Calls to other tools use their JSON arguments as they are.
+func ToolCommand(t tool.Tool, arguments string) string {
+	if script, ok := t.(*ScriptTool); ok {
+		args, err := script.Args(arguments)
+		if err != nil {
+			return filepath.ToSlash(script.Script)
+		}
+		return strings.Join(append([]string{filepath.ToSlash(script.Script)}, args...), " ")
+	}
+	var params struct {
+		ScriptPath string   `json:"scriptPath"`
+		Args       []string `json:"args"`
+		FilePath   string   `json:"filePath"`
+		Query      string   `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &params); err != nil {
+		return arguments
+	}
+	switch t.Name() {
+	case "run_shell_script", "run_python_script":
+		return strings.Join(append([]string{params.ScriptPath}, params.Args...), " ")
+	case "read_file", "write_file":
+		return params.FilePath
+	case "duckduckgo_search", "wikipedia_search":
+		return params.Query
+	}
+	return arguments
+}
@@ -0,0 +1,137 @@
+package goskills
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smallnest/goskills/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolRule(t *testing.T) {
+	rule, err := ParseToolRule("Bash(git:*)")
+	require.NoError(t, err)
+	assert.Equal(t, "Bash", rule.Name)
+	assert.Equal(t, "git:*", rule.Pattern)
+	assert.Equal(t, "Bash(git:*)", rule.String())
+
+	for _, bad := range []string{"", "(git:*)", "Bash(git:*", "Bash()", "run_[a-"} {
+		_, err := ParseToolRule(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestToolRule_Matches(t *testing.T) {
+	tests := []struct {
+		rule    string
+		tool    string
+		command string
+		want    bool
+	}{
+		{"read_file", "read_file", "notes.txt", true},
+		{"read_file", "write_file", "notes.txt", false},
+		{"run_scripts_*", "run_scripts_fill_form_py", "", true},
+		{"run_scripts_*", "run_shell_script", "", false},
+		{"Bash", "run_scripts_fill_form_py", "", true},
+		{"Bash", "run_shell_script", "", true},
+		{"Bash", "read_file", "", false},
+		{"Read", "read_references_forms_md", "", true},
+		{"WebSearch", "wikipedia_search", "go", true},
+		{"Bash(git:*)", "run_shell_script", "git", true},
+		{"Bash(git:*)", "run_shell_script", "git status --short", true},
+		{"Bash(git:*)", "run_shell_script", "gitk", false},
+		{"Bash(git:*)", "read_file", "git", false},
+		{"run_scripts_fill_form_py(scripts/fill_form.py --dry-run*)", "run_scripts_fill_form_py", "scripts/fill_form.py --dry-run in.pdf", true},
+		{"run_scripts_fill_form_py(scripts/fill_form.py --dry-run*)", "run_scripts_fill_form_py", "scripts/fill_form.py in.pdf", false},
+		{"Read(docs/*)", "read_file", "docs/a/b.md", true},
+		{"Read(docs/*)", "read_file", "src/main.go", false},
+	}
+	for _, tt := range tests {
+		rule, err := ParseToolRule(tt.rule)
+		require.NoError(t, err, tt.rule)
+		assert.Equal(t, tt.want, rule.Matches(tt.tool, tt.command), "%s on %s(%s)", tt.rule, tt.tool, tt.command)
+	}
+}
+
+func TestToolPolicy(t *testing.T) {
+	policy, err := NewToolPolicy(
+		[]string{"read_file", "run_scripts_*", "Bash(git:*)", "Bash("},
+		[]string{"run_scripts_danger_*", "Bash(git push:*)"},
+	)
+	assert.ErrorContains(t, err, "allowed-tools: tool rule 'Bash(' has an unclosed argument pattern")
+	require.Len(t, policy.Allow, 3, "invalid entries are left out")
+
+	assert.True(t, policy.Exposes("read_file"))
+	assert.True(t, policy.Exposes("run_scripts_convert_py"))
+	assert.True(t, policy.Exposes("run_shell_script"), "allowed for git commands")
+	assert.False(t, policy.Exposes("run_scripts_danger_wipe_sh"), "deny wins over allow")
+	assert.False(t, policy.Exposes("write_file"))
+
+	assert.NoError(t, policy.Check("read_file", "notes.txt"))
+	assert.NoError(t, policy.Check("run_shell_script", "git log"))
+	assert.ErrorContains(t, policy.Check("run_shell_script", "git push origin main"), "denied by disallowed-tools entry 'Bash(git push:*)'")
+	assert.ErrorContains(t, policy.Check("run_shell_script", "rm -rf /"), "is not permitted by allowed-tools")
+	assert.ErrorContains(t, policy.Check("write_file", "notes.txt"), "tool 'write_file' is not in allowed-tools")
+
+	open, err := NewToolPolicy(nil, []string{"write_file"})
+	require.NoError(t, err)
+	assert.True(t, open.Exposes("duckduckgo_search"), "without allow rules every tool is allowed")
+	assert.False(t, open.Exposes("write_file"))
+
+	var none *ToolPolicy
+	assert.True(t, none.Exposes("write_file"))
+	assert.NoError(t, none.Check("write_file", ""))
+}
+
+func TestGenerateTools_AllowedTools(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tools/SKILL.md": {Data: []byte(`---
+name: tools
+description: Scripts with an allowlist.
+allowed-tools:
+  - read_file
+  - run_scripts_convert_*
+  - read_references_*
+disallowed-tools:
+  - run_scripts_convert_legacy_py
+  - Bash(
+---
+`)},
+		"tools/scripts/convert.py":        {Data: []byte("pass"), Mode: 0o755},
+		"tools/scripts/convert_legacy.py": {Data: []byte("pass"), Mode: 0o755},
+		"tools/scripts/wipe.sh":           {Data: []byte("rm -rf out"), Mode: 0o755},
+		"tools/references/forms.md":       {Data: []byte("# Forms")},
+	}
+	pkg, err := ParseSkillPackage(filepath.Join(writeFS(t, fsys), "tools"))
+	require.NoError(t, err)
+
+	tools, scripts := GenerateTools(*pkg)
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Function.Name)
+	}
+	assert.Equal(t, []string{"read_file", "run_scripts_convert_py"}, names, "allowed-tools applies to script tools too")
+	assert.Len(t, scripts, 1)
+
+	refTools, _ := GenerateReferenceTools(*pkg)
+	require.Len(t, refTools, 1)
+	assert.Equal(t, "read_references_forms_md", refTools[0].Function.Name)
+
+	registry := SkillTools(*pkg)
+	lookup := func(name string) tool.Tool {
+		found, ok := registry.Lookup(name)
+		require.True(t, ok, name)
+		return found
+	}
+	assert.Equal(t, "scripts/convert.py --in a.pdf", ToolCommand(lookup("run_scripts_convert_py"), `{"args": ["--in", "a.pdf"]}`))
+	assert.Equal(t, "notes.txt", ToolCommand(lookup("read_file"), `{"filePath": "notes.txt"}`))
+	assert.Equal(t, "build.sh -v", ToolCommand(tool.BaseTools("")[0], `{"scriptPath": "build.sh", "args": ["-v"]}`))
+
+	var rules []string
+	for _, d := range Validate(pkg) {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{RuleToolRuleFormat}, rules)
+}
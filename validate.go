@@ -0,0 +1,408 @@
+package goskills
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rule IDs reported by Validate.
+const (
+	RuleParse               = "parse"
+	RuleNameRequired        = "name-required"
+	RuleNameFormat          = "name-format"
+	RuleNameDirectory       = "name-matches-directory"
+	RuleDescriptionRequired = "description-required"
+	RuleLicenseFile         = "license-file"
+	RuleUnknownField        = "unknown-field"
+	RuleDanglingReference   = "dangling-reference"
+	RuleSkillIgnore         = "skillignore"
+	RuleFileSize            = "file-size"
+	RuleSkillSize           = "skill-size"
+	RuleSymlinkEscape       = "symlink-escape"
+	RuleVersionFormat       = "version-format"
+	RuleRequiresFormat      = "requires-format"
+	RuleScriptSpec          = "script-spec"
+	RuleToolNameCollision   = "tool-name-collision"
+	RuleToolRuleFormat      = "tool-rule-format"
+)
+
+// ruleDescriptions holds a short, human-readable summary for every rule ID.
+var ruleDescriptions = map[string]string{
+	RuleParse:               "SKILL.md must exist and start with valid YAML frontmatter.",
+	RuleNameRequired:        "The frontmatter must define a 'name'.",
+	RuleNameFormat:          "The skill name must be hyphen-case: lowercase alphanumerics separated by single hyphens.",
+	RuleNameDirectory:       "The skill name must match the name of the directory containing SKILL.md.",
+	RuleDescriptionRequired: "The frontmatter must define a non-empty 'description'.",
+	RuleLicenseFile:         "A license that names a file must point to a file bundled with the skill.",
+	RuleUnknownField:        "Frontmatter fields outside the spec should be placed under 'metadata'.",
+	RuleDanglingReference:   "Links in the SKILL.md body should point to files bundled with the skill.",
+	RuleSkillIgnore:         "Every pattern in .skillignore must be valid .gitignore syntax.",
+	RuleFileSize:            "Bundled files should not exceed the configured per-file size limit.",
+	RuleSkillSize:           "The files of a skill should not exceed the configured total size limit.",
+	RuleSymlinkEscape:       "Symbolic links in a skill should resolve to files inside the skill directory.",
+	RuleVersionFormat:       "The version should be a semantic version, such as 1.2.0.",
+	RuleRequiresFormat:      "Each 'requires' entry must name another skill and give a valid version constraint.",
+	RuleScriptSpec:          "Script interfaces declared in sidecar specs or headers should be well-formed.",
+	RuleToolNameCollision:   "Scripts and reference documents should map to distinct tool names without disambiguation.",
+	RuleToolRuleFormat:      "Each 'allowed-tools' and 'disallowed-tools' entry must be a tool name or glob, optionally followed by an argument pattern in parentheses.",
+}
+
+// RuleDescription returns the summary of a rule ID, or an empty string if the rule is unknown.
+func RuleDescription(rule string) string {
+	return ruleDescriptions[rule]
+}
+
+// Rules returns all rule IDs reported by Validate, sorted.
+func Rules() []string {
+	rules := make([]string, 0, len(ruleDescriptions))
+	for r := range ruleDescriptions {
+		rules = append(rules, r)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// Diagnostic describes a single spec-conformance problem found in a skill package.
+// Line and Column are 1-based; zero means the position is unknown.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+// String formats the diagnostic as "file:line:column: severity: message [rule]".
+func (d Diagnostic) String() string {
+	pos := d.File
+	if d.Line > 0 {
+		pos = fmt.Sprintf("%s:%d", pos, d.Line)
+		if d.Column > 0 {
+			pos = fmt.Sprintf("%s:%d", pos, d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", pos, d.Severity, d.Message, d.Rule)
+}
+
+// HasErrors reports whether any of the diagnostics has error severity.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseErrorDiagnostic converts an error returned by ParseSkillPackage for dir into a Diagnostic.
+// Frontmatter errors carry their line number into the diagnostic.
+func ParseErrorDiagnostic(dir string, err error) Diagnostic {
+	d := Diagnostic{
+		Severity: SeverityError,
+		Rule:     RuleParse,
+		Message:  err.Error(),
+		File:     filepath.Join(dir, "SKILL.md"),
+	}
+	var fmErr *FrontmatterError
+	if errors.As(err, &fmErr) {
+		d.Message = fmErr.Error()
+		d.Line = fmErr.Line
+	}
+	return d
+}
+
+// licenseFilePattern matches tokens in a license string that look like a bundled file name.
+var licenseFilePattern = regexp.MustCompile(`(?i)\b(?:[\w.-]+\.(?:txt|md|rst)|(?:LICENSE|LICENCE|COPYING)\b[\w.-]*)`)
+
+// Validate checks a parsed skill package against the Agent Skills spec and
+// returns the problems found, ordered by position with those in SKILL.md
+// first, followed by the package's Warnings. An empty result means the
+// package conforms.
+func Validate(pkg *SkillPackage) []Diagnostic {
+	v := validator{
+		pkg:  pkg,
+		file: filepath.Join(pkg.Path, "SKILL.md"),
+	}
+
+	v.checkName()
+	v.checkDescription()
+	v.checkLicense()
+	v.checkVersion()
+	v.checkRequires()
+	v.checkToolRules("allowed-tools", v.pkg.Meta.AllowedTools)
+	v.checkToolRules("disallowed-tools", v.pkg.Meta.DisallowedTools)
+	v.checkUnknownFields()
+	v.checkReferences()
+	v.checkScriptSpecs()
+	v.checkToolNames()
+
+	// SKILL.md comes first, then the other files by path.
+	sort.SliceStable(v.diags, func(i, j int) bool {
+		a, b := v.diags[i], v.diags[j]
+		if a.File != b.File {
+			if a.File == v.file || b.File == v.file {
+				return a.File == v.file
+			}
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return append(v.diags, pkg.Warnings...)
+}
+
+// validator accumulates diagnostics for a single package.
+type validator struct {
+	pkg   *SkillPackage
+	file  string
+	diags []Diagnostic
+}
+
+func (v *validator) report(sev Severity, rule string, node *yaml.Node, format string, args ...interface{}) {
+	line, column := 1, 1
+	if node != nil {
+		line, column = node.Line, node.Column
+	}
+	v.reportAt(sev, rule, line, column, format, args...)
+}
+
+func (v *validator) reportAt(sev Severity, rule string, line, column int, format string, args ...interface{}) {
+	v.diags = append(v.diags, Diagnostic{
+		Severity: sev,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+		File:     v.file,
+		Line:     line,
+		Column:   column,
+	})
+}
+
+// lookup returns the key and value nodes of a frontmatter field, or nils if absent
+// or if the package carries no positional information.
+func (v *validator) lookup(key string) (*yaml.Node, *yaml.Node) {
+	node := v.pkg.frontmatter
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func (v *validator) checkName() {
+	name := v.pkg.Meta.Name
+	_, value := v.lookup("name")
+	if strings.TrimSpace(name) == "" {
+		v.report(SeverityError, RuleNameRequired, value, "missing required field 'name'")
+		return
+	}
+	if problem := checkSkillName(name); problem != "" {
+		v.report(SeverityError, RuleNameFormat, value, "name '%s' is not valid hyphen-case: %s", name, problem)
+	}
+	if dir := v.pkg.dirName(); dir != "" && dir != name {
+		v.report(SeverityError, RuleNameDirectory, value, "name '%s' does not match directory name '%s'", name, dir)
+	}
+}
+
+// dirName returns the name of the skill's directory, resolving a relative OS
+// path such as ".", or "" for the root of an fs.FS, which has no name.
+func (p *SkillPackage) dirName() string {
+	if p.fsys != nil && !p.native {
+		if dir := path.Base(p.dir); dir != "." && dir != "/" {
+			return dir
+		}
+		return ""
+	}
+	if abs, err := filepath.Abs(p.Path); err == nil {
+		return filepath.Base(abs)
+	}
+	return filepath.Base(p.Path)
+}
+
+// ValidateSkillName returns an error unless name is a valid skill name:
+// non-empty hyphen-case, as required by the Agent Skills spec.
+func ValidateSkillName(name string) error {
+	if name == "" {
+		return errors.New("skill name is empty")
+	}
+	if problem := checkSkillName(name); problem != "" {
+		return fmt.Errorf("name '%s' is not valid hyphen-case: %s", name, problem)
+	}
+	return nil
+}
+
+// checkSkillName returns a description of why name is not valid hyphen-case, or an empty string.
+func checkSkillName(name string) string {
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") {
+		return "must not start or end with a hyphen"
+	}
+	if strings.Contains(name, "--") {
+		return "must not contain consecutive hyphens"
+	}
+	for _, r := range name {
+		switch {
+		case r == '-':
+		case unicode.IsDigit(r):
+		case unicode.IsLetter(r) && !unicode.IsUpper(r) && !unicode.IsTitle(r):
+		case unicode.IsUpper(r) || unicode.IsTitle(r):
+			return "must be lowercase"
+		default:
+			return fmt.Sprintf("contains invalid character %q", r)
+		}
+	}
+	return ""
+}
+
+func (v *validator) checkDescription() {
+	if strings.TrimSpace(v.pkg.Meta.Description) == "" {
+		_, value := v.lookup("description")
+		v.report(SeverityError, RuleDescriptionRequired, value, "missing required field 'description'")
+	}
+}
+
+func (v *validator) checkLicense() {
+	license := v.pkg.Meta.License
+	if license == "" {
+		return
+	}
+	_, value := v.lookup("license")
+	for _, name := range licenseFilePattern.FindAllString(license, -1) {
+		name = strings.TrimRight(name, ".")
+		if _, err := v.pkg.statResource(name); err != nil {
+			v.report(SeverityError, RuleLicenseFile, value, "license refers to '%s', which is not bundled with the skill", name)
+		}
+	}
+}
+
+func (v *validator) checkVersion() {
+	version := v.pkg.Meta.Version
+	if version == "" {
+		return
+	}
+	if _, err := ParseVersion(version); err != nil {
+		_, value := v.lookup("version")
+		v.report(SeverityWarning, RuleVersionFormat, value, "%v", err)
+	}
+}
+
+func (v *validator) checkRequires() {
+	_, value := v.lookup("requires")
+	seen := make(map[string]bool)
+	for i, dep := range v.pkg.Meta.Requires {
+		node := value
+		if value != nil && value.Kind == yaml.SequenceNode && i < len(value.Content) {
+			node = value.Content[i]
+		}
+		switch {
+		case dep.Name == "":
+			v.report(SeverityError, RuleRequiresFormat, node, "dependency has no skill name")
+			continue
+		case dep.Name == v.pkg.Meta.Name:
+			v.report(SeverityError, RuleRequiresFormat, node, "skill '%s' requires itself", dep.Name)
+			continue
+		case seen[dep.Name]:
+			v.report(SeverityError, RuleRequiresFormat, node, "skill '%s' is required more than once", dep.Name)
+		}
+		seen[dep.Name] = true
+		if problem := checkSkillName(dep.Name); problem != "" {
+			v.report(SeverityError, RuleRequiresFormat, node, "dependency name '%s' is not valid hyphen-case: %s", dep.Name, problem)
+		}
+		if _, err := dep.Constraint(); err != nil {
+			v.report(SeverityError, RuleRequiresFormat, node, "dependency '%s': %v", dep.Name, err)
+		}
+	}
+}
+
+// checkToolRules reports entries of the allowed-tools or disallowed-tools
+// field key that cannot be parsed. They are left out of the ToolPolicy.
+func (v *validator) checkToolRules(key string, entries []string) {
+	_, value := v.lookup(key)
+	for i, entry := range entries {
+		node := value
+		if value != nil && value.Kind == yaml.SequenceNode && i < len(value.Content) {
+			node = value.Content[i]
+		}
+		if _, err := ParseToolRule(entry); err != nil {
+			v.report(SeverityError, RuleToolRuleFormat, node, "%s: %v", key, err)
+		}
+	}
+}
+
+// checkScriptSpecs reports scripts whose declared interface cannot be read.
+// Their tools fall back to an untyped list of arguments.
+func (v *validator) checkScriptSpecs() {
+	for _, f := range v.pkg.Resources.FilesOfKind(KindScript) {
+		if !v.pkg.contentAllowed(f) {
+			continue
+		}
+		if _, err := v.pkg.ScriptSpec(f.Path); err != nil {
+			v.diags = append(v.diags, Diagnostic{
+				Severity: SeverityWarning,
+				Rule:     RuleScriptSpec,
+				Message:  err.Error(),
+				File:     filepath.Join(v.pkg.Path, f.Path),
+				Line:     1,
+			})
+		}
+	}
+}
+
+// checkToolNames reports files whose tool names collide. The tools stay
+// usable, but the renamed one gets a name the author did not choose.
+func (v *validator) checkToolNames() {
+	for _, c := range ToolNameCollisions(*v.pkg) {
+		v.diags = append(v.diags, Diagnostic{
+			Severity: SeverityWarning,
+			Rule:     RuleToolNameCollision,
+			Message:  c.String(),
+			File:     filepath.Join(v.pkg.Path, c.Other),
+			Line:     1,
+		})
+	}
+}
+
+func (v *validator) checkUnknownFields() {
+	keys := make([]string, 0, len(v.pkg.Meta.Extra))
+	for k := range v.pkg.Meta.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key, _ := v.lookup(k)
+		v.report(SeverityWarning, RuleUnknownField, key, "field '%s' is not defined by the Agent Skills spec; consider moving it under 'metadata'", k)
+	}
+}
+
+// checkReferences reports body links to files that are not bundled with the
+// skill. Inline code is not checked, since it often names files the skill
+// creates rather than ones it ships.
+func (v *validator) checkReferences() {
+	for _, ref := range v.pkg.References {
+		if ref.Source == ReferenceLink && !ref.Exists {
+			v.reportAt(SeverityWarning, RuleDanglingReference, ref.Line, ref.Column, "link to '%s' does not resolve to a file in the skill", ref.Target)
+		}
+	}
+}
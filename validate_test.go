@@ -0,0 +1,161 @@
+package goskills
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_ExampleSkills(t *testing.T) {
+	skills, err := ParseSkillPackages("./examples/skills")
+	require.NoError(t, err)
+	for _, skill := range skills {
+		assert.Empty(t, Validate(skill), "skill %s", skill.Path)
+	}
+}
+
+func TestValidate_NameInCurrentDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pdf")
+	writeSkill(t, dir, "name: pdf\ndescription: Works with PDFs.")
+	t.Chdir(dir)
+	pkg, err := ParseSkillPackage(".")
+	require.NoError(t, err)
+	assert.Empty(t, Validate(pkg), "the name of '.' is that of the working directory")
+
+	pkg, err = ParseSkillPackageFS(os.DirFS(dir), ".")
+	require.NoError(t, err)
+	assert.Empty(t, Validate(pkg), "the root of an fs.FS has no name to match")
+
+	writeSkill(t, dir, "name: docx\ndescription: Works with PDFs.")
+	pkg, err = ParseSkillPackage(".")
+	require.NoError(t, err)
+	diags := Validate(pkg)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "name 'docx' does not match directory name 'pdf'", diags[0].Message)
+}
+
+func TestValidate_Violations(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillPath := filepath.Join(tmpDir, "my-skill")
+	require.NoError(t, os.Mkdir(skillPath, 0755))
+
+	skillContent := `---
+name: My_Skill
+description: ""
+license: See LICENSE.txt
+owner: someone
+---
+# Body
+See [the guide](guide.md) and write ` + "`out.pdf`" + `.
+`
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(skillContent), 0644))
+
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+
+	diags := Validate(pkg)
+	require.True(t, HasErrors(diags))
+
+	byRule := make(map[string]Diagnostic)
+	for _, d := range diags {
+		byRule[d.Rule] = d
+	}
+
+	require.Contains(t, byRule, RuleNameFormat)
+	assert.Equal(t, 2, byRule[RuleNameFormat].Line)
+	assert.Equal(t, 7, byRule[RuleNameFormat].Column)
+	assert.Equal(t, filepath.Join(skillPath, "SKILL.md"), byRule[RuleNameFormat].File)
+
+	require.Contains(t, byRule, RuleNameDirectory)
+	require.Contains(t, byRule, RuleDescriptionRequired)
+	assert.Equal(t, 3, byRule[RuleDescriptionRequired].Line)
+
+	require.Contains(t, byRule, RuleLicenseFile)
+	assert.Contains(t, byRule[RuleLicenseFile].Message, "LICENSE.txt")
+
+	require.Contains(t, byRule, RuleUnknownField)
+	assert.Equal(t, SeverityWarning, byRule[RuleUnknownField].Severity)
+	assert.Equal(t, 5, byRule[RuleUnknownField].Line)
+
+	require.Contains(t, byRule, RuleDanglingReference)
+	assert.Equal(t, SeverityWarning, byRule[RuleDanglingReference].Severity)
+	assert.Equal(t, 8, byRule[RuleDanglingReference].Line)
+	assert.Equal(t, 17, byRule[RuleDanglingReference].Column)
+	assert.Contains(t, byRule[RuleDanglingReference].Message, "guide.md")
+	for _, d := range diags {
+		assert.NotContains(t, d.Message, "out.pdf", "inline code is not checked")
+	}
+
+	// Bundling the license file clears the license diagnostic.
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "LICENSE.txt"), []byte("terms"), 0644))
+	for _, d := range Validate(pkg) {
+		assert.NotEqual(t, RuleLicenseFile, d.Rule)
+	}
+}
+
+func TestValidate_VersionAndRequires(t *testing.T) {
+	skillPath := filepath.Join(t.TempDir(), "docx")
+	writeSkill(t, skillPath, "name: docx\ndescription: Word documents.\nversion: '1.0'\nrequires:\n  - ooxml ^1.2\n  - Bad_Name\n  - fonts >=x.y\n  - docx")
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+
+	var got []string
+	for _, d := range Validate(pkg) {
+		got = append(got, fmt.Sprintf("%d:%s:%s", d.Line, d.Severity, d.Rule))
+	}
+	assert.Equal(t, []string{
+		"4:warning:" + RuleVersionFormat,
+		"7:error:" + RuleRequiresFormat,
+		"8:error:" + RuleRequiresFormat,
+		"9:error:" + RuleRequiresFormat,
+	}, got)
+}
+
+func TestValidate_OrderedByPosition(t *testing.T) {
+	pkg := scriptSpecSkill(t, map[string]string{
+		"scripts/broken.sh":        "echo broken\n",
+		"scripts/broken.tool.yaml": "parameters:\n  - name: x\n    type: date\n",
+		"scripts/a-b.py":           "pass\n",
+		"scripts/a_b.py":           "pass\n",
+	})
+	pkg.Meta.Version = "one"
+
+	var got []string
+	for _, d := range Validate(pkg) {
+		rel, err := filepath.Rel(pkg.Path, d.File)
+		require.NoError(t, err)
+		got = append(got, fmt.Sprintf("%s:%s", filepath.ToSlash(rel), d.Rule))
+	}
+	assert.Equal(t, []string{
+		"SKILL.md:" + RuleVersionFormat,
+		"scripts/a_b.py:" + RuleToolNameCollision,
+		"scripts/broken.sh:" + RuleScriptSpec,
+	}, got)
+}
+
+func TestValidate_MissingName(t *testing.T) {
+	pkg := &SkillPackage{Path: "/skills/unnamed", Meta: SkillMeta{Description: "desc"}}
+	diags := Validate(pkg)
+	require.Len(t, diags, 1)
+	assert.Equal(t, RuleNameRequired, diags[0].Rule)
+	assert.Equal(t, 1, diags[0].Line)
+}
+
+func TestCheckSkillName(t *testing.T) {
+	assert.Empty(t, checkSkillName("pdf"))
+	assert.Empty(t, checkSkillName("mcp-builder2"))
+	assert.Empty(t, checkSkillName("café-tools"))
+	assert.NotEmpty(t, checkSkillName("Pdf"))
+	assert.NotEmpty(t, checkSkillName("-pdf"))
+	assert.NotEmpty(t, checkSkillName("pdf--tools"))
+	assert.NotEmpty(t, checkSkillName("pdf_tools"))
+	assert.NotEmpty(t, checkSkillName("pdf tools"))
+
+	assert.NoError(t, ValidateSkillName("pdf-tools"))
+	assert.Error(t, ValidateSkillName(""))
+	assert.ErrorContains(t, ValidateSkillName("PDF"), "must be lowercase")
+}
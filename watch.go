@@ -0,0 +1,124 @@
+package goskills
+
+import (
+	"context"
+	"time"
+)
+
+// RegistryEventType is the kind of change reported by Registry.Watch.
+type RegistryEventType string
+
+const (
+	SkillAdded   RegistryEventType = "added"   // A new skill directory was indexed
+	SkillUpdated RegistryEventType = "updated" // The SKILL.md of a known skill changed and was indexed again
+	SkillRemoved RegistryEventType = "removed" // A skill directory or its SKILL.md disappeared
+	SkillFailed  RegistryEventType = "failed"  // A SKILL.md failed to parse; a known skill keeps its last good version
+)
+
+// RegistryEvent describes a change to a skill directory in a watched Registry.
+type RegistryEvent struct {
+	Type  RegistryEventType `json:"type"`
+	Name  string            `json:"name,omitempty"` // Skill name; for SkillFailed, the name of the version still served
+	Path  string            `json:"path"`           // Skill directory
+	Skill *RegisteredSkill  `json:"-"`              // New version, or the removed or still served one
+	Err   error             `json:"-"`              // Parse error of a SkillFailed event
+}
+
+// notifier delivers the paths of changed files below the watched roots.
+type notifier interface {
+	Changes() <-chan string
+	Close() error
+}
+
+// Watch keeps the registry up to date with the skill roots until ctx is
+// done, and reports every change on the returned channel, which is closed when
+// watching stops. Only skill directories whose SKILL.md changed are parsed
+// again. Changes are picked up through file system notifications where the
+// platform supports them (inotify on Linux), with a debounce so that bursts of
+// writes cause a single update, and by polling the roots otherwise.
+//
+// Notifications cover the roots that exist when Watch is called; roots
+// created later are only seen when polling. The caller must drain the channel.
+func (r *Registry) Watch(ctx context.Context, opts ...WatchOption) <-chan RegistryEvent {
+	o := newWatchOptions(opts)
+
+	var n notifier
+	if !o.polling {
+		paths := make([]string, 0, len(r.roots))
+		for _, root := range r.roots {
+			paths = append(paths, root.Path)
+		}
+		// Fall back to polling when notifications are unavailable.
+		n, _ = newNotifier(paths, r.opts)
+	}
+
+	events := make(chan RegistryEvent, 16)
+	go r.watch(ctx, n, o, events)
+	return events
+}
+
+// watch is the event loop of Watch.
+func (r *Registry) watch(ctx context.Context, n notifier, o *watchOptions, events chan<- RegistryEvent) {
+	defer close(events)
+
+	var changes <-chan string
+	var poll <-chan time.Time
+	var ticker *time.Ticker
+	startPolling := func() {
+		ticker = time.NewTicker(o.pollInterval)
+		poll = ticker.C
+	}
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+	if n != nil {
+		defer n.Close()
+		changes = n.Changes()
+	} else {
+		startPolling()
+	}
+
+	debounce := time.NewTimer(o.debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	dirty := make(map[string]bool)
+	update := func() bool {
+		evs, _ := r.sync(dirty, false)
+		dirty = make(map[string]bool)
+		for _, ev := range evs {
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case p, ok := <-changes:
+			if !ok {
+				// The notifier failed; continue by polling.
+				changes = nil
+				startPolling()
+				continue
+			}
+			dirty[p] = true
+			debounce.Reset(o.debounce)
+		case <-debounce.C:
+			if !update() {
+				return
+			}
+		case <-poll:
+			if !update() {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,238 @@
+//go:build linux
+
+package goskills
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask selects the events that can change what a skill directory holds.
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ATTRIB | syscall.IN_DELETE_SELF
+
+// inotifyNotifier watches directory trees with inotify. inotify watches are
+// not recursive, so every directory below the roots is watched, and
+// directories created later are added as they appear. Directories the parser
+// skips, by name or through a skill's .skillignore, are not watched.
+type inotifyNotifier struct {
+	fd      int      // The inotify descriptor
+	file    *os.File // fd, registered with the runtime poller so that Close interrupts Read
+	changes chan string
+	roots   []string
+	opts    *parseOptions
+
+	mu      sync.Mutex
+	watches map[int32]string // Watch descriptor to directory
+}
+
+// newNotifier starts watching the directory trees below roots, skipping the
+// directories that o ignores. Roots that do not exist are skipped.
+func newNotifier(roots []string, o *parseOptions) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	n := &inotifyNotifier{
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		changes: make(chan string, 256),
+		roots:   roots,
+		opts:    o,
+		watches: make(map[int32]string),
+	}
+	for _, root := range roots {
+		if err := n.addTree(root); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			n.file.Close()
+			return nil, err
+		}
+	}
+	go n.readEvents()
+	return n, nil
+}
+
+// addTree watches dir and every directory below it, unless dir, below a
+// root, is in an ignored directory.
+func (n *inotifyNotifier) addTree(dir string) error {
+	if slices.Contains(n.roots, dir) {
+		return n.addDir(dir, "", nil, true)
+	}
+	for _, root := range n.roots {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+			if n.opts.ignored(name) {
+				return nil
+			}
+		}
+	}
+	skill, matcher := n.enclosingSkill(filepath.Dir(dir))
+	if matcher != nil {
+		// The parser does not descend into excluded directories, so neither
+		// are their subdirectories watched.
+		rel := skillRel(skill, dir)
+		for i := range rel {
+			if rel[i] == '/' && matcher.excluded(rel[:i], true) {
+				return nil
+			}
+		}
+		if matcher.excluded(rel, true) {
+			return nil
+		}
+	}
+	return n.addDir(dir, skill, matcher, false)
+}
+
+// addDir watches dir and recurses into its subdirectories, except those
+// that are ignored by name or excluded by the .skillignore of skill, the
+// skill directory dir is in, whose patterns are in matcher. Subdirectories
+// of top, the directory addTree was called with, that vanish or cannot be
+// read are skipped; other errors, such as running out of watches, are
+// returned.
+func (n *inotifyNotifier) addDir(dir, skill string, matcher *ignoreMatcher, top bool) error {
+	if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err == nil {
+		skill, matcher = dir, skillIgnoreMatcher(dir)
+	}
+	wd, err := syscall.InotifyAddWatch(n.fd, dir, inotifyMask)
+	if err != nil {
+		if !top && errors.Is(err, fs.ErrNotExist) {
+			return nil // Vanished subdirectory
+		}
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+	n.mu.Lock()
+	n.watches[int32(wd)] = dir
+	n.mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if top {
+			return err
+		}
+		return nil // Vanished or unreadable subdirectory
+	}
+	for _, e := range entries {
+		if !e.IsDir() || n.opts.ignored(e.Name()) {
+			continue
+		}
+		sub := filepath.Join(dir, e.Name())
+		if matcher != nil && matcher.excluded(skillRel(skill, sub), true) {
+			continue
+		}
+		if err := n.addDir(sub, skill, matcher, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enclosingSkill returns the nearest skill directory at or above dir, up to
+// the root dir is in, with the patterns of its .skillignore. It returns a nil
+// matcher when dir is not inside a skill.
+func (n *inotifyNotifier) enclosingSkill(dir string) (string, *ignoreMatcher) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err == nil {
+			return dir, skillIgnoreMatcher(dir)
+		}
+		parent := filepath.Dir(dir)
+		if slices.Contains(n.roots, dir) || parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// skillIgnoreMatcher returns the exclusions the parser applies to the files
+// of the skill in dir: the default excludes and its .skillignore.
+func skillIgnoreMatcher(dir string) *ignoreMatcher {
+	matcher := &ignoreMatcher{}
+	matcher.addPatterns(defaultExcludes)
+	if data, err := os.ReadFile(filepath.Join(dir, SkillIgnoreFile)); err == nil {
+		matcher.addPatterns(strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"))
+	}
+	return matcher
+}
+
+// skillRel returns the slash-separated path of p relative to the skill directory skill.
+func skillRel(skill, p string) string {
+	rel, _ := filepath.Rel(skill, p)
+	return filepath.ToSlash(rel)
+}
+
+// readEvents decodes inotify events into changed paths until the notifier is closed.
+func (n *inotifyNotifier) readEvents() {
+	defer close(n.changes)
+
+	var buf [64 * (syscall.SizeofInotifyEvent + syscall.NAME_MAX + 1)]byte
+	for {
+		count, err := n.file.Read(buf[:])
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= count; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(ev.Len)]
+			offset += syscall.SizeofInotifyEvent + int(ev.Len)
+
+			n.mu.Lock()
+			dir, ok := n.watches[ev.Wd]
+			if ev.Mask&syscall.IN_IGNORED != 0 {
+				delete(n.watches, ev.Wd)
+			}
+			n.mu.Unlock()
+			if ev.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				// Events were lost; an empty path still triggers a rescan.
+				n.send("")
+				continue
+			}
+			if !ok {
+				continue
+			}
+
+			p := dir
+			if name := string(nameBytes[:clen(nameBytes)]); name != "" {
+				p = filepath.Join(dir, name)
+			}
+			if ev.Mask&syscall.IN_ISDIR != 0 && ev.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+				n.addTree(p)
+			}
+			n.send(p)
+		}
+	}
+}
+
+// send delivers a changed path without blocking. When the buffer is full the
+// path is dropped; the rescan it would have triggered is already pending.
+func (n *inotifyNotifier) send(p string) {
+	select {
+	case n.changes <- p:
+	default:
+	}
+}
+
+func (n *inotifyNotifier) Changes() <-chan string {
+	return n.changes
+}
+
+func (n *inotifyNotifier) Close() error {
+	return n.file.Close()
+}
+
+// clen returns the length of the NUL-terminated string in b.
+func clen(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return len(b)
+}
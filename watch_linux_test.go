@@ -0,0 +1,48 @@
+//go:build linux
+
+package goskills
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInotifyNotifier_SkipsIgnoredDirectories(t *testing.T) {
+	root := t.TempDir()
+	skill := filepath.Join(root, "alpha")
+	writeSkill(t, skill, "name: alpha\ndescription: First.")
+	require.NoError(t, os.WriteFile(filepath.Join(skill, SkillIgnoreFile), []byte("build/\n"), 0o644))
+	for _, dir := range []string{".git/objects", "alpha/node_modules/left-pad", "alpha/build/out", "alpha/scripts/lib", "vendor"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+
+	n, err := newNotifier([]string{root}, newParseOptions([]ParseOption{WithIgnore("vendor")}))
+	require.NoError(t, err)
+	defer n.Close()
+	in := n.(*inotifyNotifier)
+	watched := func() []string {
+		in.mu.Lock()
+		defer in.mu.Unlock()
+		var dirs []string
+		for _, dir := range in.watches {
+			rel, err := filepath.Rel(root, dir)
+			require.NoError(t, err)
+			dirs = append(dirs, filepath.ToSlash(rel))
+		}
+		sort.Strings(dirs)
+		return dirs
+	}
+	assert.Equal(t, []string{".", "alpha", "alpha/scripts", "alpha/scripts/lib"}, watched())
+
+	// Directories created later follow the same rules.
+	for _, dir := range []string{"alpha/build/cache", "alpha/assets", "alpha/assets/node_modules", "alpha/node_modules/left-pad/lib"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+		require.NoError(t, in.addTree(filepath.Join(root, dir)))
+	}
+	assert.Equal(t, []string{".", "alpha", "alpha/assets", "alpha/scripts", "alpha/scripts/lib"}, watched())
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package goskills
+
+import "errors"
+
+// newNotifier reports that change notifications are unavailable, so Watch polls.
+func newNotifier(roots []string, o *parseOptions) (notifier, error) {
+	return nil, errors.New("file change notifications are not supported on this platform")
+}
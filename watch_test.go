@@ -0,0 +1,136 @@
+package goskills
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Watch(t *testing.T) {
+	for name, opts := range map[string][]WatchOption{
+		"notify":  {WithDebounce(20 * time.Millisecond)},
+		"polling": {WithPolling(), WithPollInterval(20 * time.Millisecond)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeSkill(t, filepath.Join(root, "alpha"), "name: alpha\ndescription: First.")
+
+			registry, err := NewRegistry([]Root{{Path: root}})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			events := registry.Watch(ctx, opts...)
+
+			next := func() RegistryEvent {
+				t.Helper()
+				select {
+				case ev := <-events:
+					return ev
+				case <-time.After(5 * time.Second):
+					t.Fatal("timed out waiting for a registry event")
+					return RegistryEvent{}
+				}
+			}
+
+			writeSkill(t, filepath.Join(root, "beta"), "name: beta\ndescription: Second.")
+			ev := next()
+			assert.Equal(t, SkillAdded, ev.Type)
+			assert.Equal(t, "beta", ev.Name)
+			_, ok := registry.Lookup("beta")
+			assert.True(t, ok)
+
+			writeSkill(t, filepath.Join(root, "alpha"), "name: alpha\ndescription: First, revised.")
+			ev = next()
+			assert.Equal(t, SkillUpdated, ev.Type)
+			assert.Equal(t, "alpha", ev.Name)
+			alpha, _ := registry.Lookup("alpha")
+			assert.Equal(t, "First, revised.", alpha.Meta.Description)
+
+			require.NoError(t, os.WriteFile(filepath.Join(root, "alpha", "SKILL.md"), []byte("---\nname: [broken\n---\n"), 0644))
+			ev = next()
+			assert.Equal(t, SkillFailed, ev.Type)
+			assert.Error(t, ev.Err)
+			alpha, ok = registry.Lookup("alpha")
+			require.True(t, ok, "the last good version is still served")
+			assert.Equal(t, "First, revised.", alpha.Meta.Description)
+			assert.Len(t, registry.Failures(), 1)
+
+			require.NoError(t, os.RemoveAll(filepath.Join(root, "beta")))
+			ev = next()
+			assert.Equal(t, SkillRemoved, ev.Type)
+			assert.Equal(t, "beta", ev.Name)
+			_, ok = registry.Lookup("beta")
+			assert.False(t, ok)
+
+			cancel()
+			for range events {
+			}
+		})
+	}
+}
+
+func TestRegistry_SyncKeepsRootOnWalkError(t *testing.T) {
+	if os.Getuid() == 0 {
+		t.Skip("permissions do not stop root from reading a directory")
+	}
+	root := t.TempDir()
+	writeSkill(t, filepath.Join(root, "alpha"), "name: alpha\ndescription: First.")
+	writeSkill(t, filepath.Join(root, "beta"), "name: beta\ndescription: Second.")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "beta", "SKILL.md"), []byte("broken"), 0644))
+	registry, err := NewRegistry([]Root{{Path: root}})
+	require.NoError(t, err)
+	require.Len(t, registry.Failures(), 1)
+
+	locked := filepath.Join(root, "locked")
+	require.NoError(t, os.Mkdir(locked, 0o000))
+	t.Cleanup(func() { os.Chmod(locked, 0o755) })
+	events, err := registry.sync(nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, events, "a root that cannot be walked keeps its skills")
+	_, ok := registry.Lookup("alpha")
+	assert.True(t, ok)
+	assert.Len(t, registry.Failures(), 1)
+
+	require.NoError(t, os.Chmod(locked, 0o755))
+	events, err = registry.sync(nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestRegistry_LoadServesLastGood(t *testing.T) {
+	root := t.TempDir()
+	writeSkill(t, filepath.Join(root, "alpha"), "name: alpha\ndescription: First.")
+	registry, err := NewRegistry([]Root{{Path: root}})
+	require.NoError(t, err)
+
+	pkg, err := registry.Load("alpha")
+	require.NoError(t, err)
+	assert.Equal(t, "First.", pkg.Meta.Description)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "alpha", "SKILL.md"), []byte("broken"), 0644))
+	pkg, err = registry.Load("alpha")
+	require.NoError(t, err)
+	assert.Equal(t, "First.", pkg.Meta.Description)
+
+	_, err = registry.Load("missing")
+	assert.Error(t, err)
+}
+
+func TestRegistry_WatchPollIntervalDefault(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		assert.Equal(t, defaultPollInterval, newWatchOptions([]WatchOption{WithPollInterval(d)}).pollInterval)
+	}
+
+	registry, err := NewRegistry([]Root{{Path: t.TempDir()}})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := registry.Watch(ctx, WithPolling(), WithPollInterval(0))
+	cancel()
+	for range events {
+	}
+}